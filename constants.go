@@ -7,3 +7,99 @@ package intvl
 const (
 	TIME_LAYOUT_DEBUG = "2006 Jan _2 15:04:05 MST" // modification of time.Stamp
 )
+
+// Distribution of time moments in interval.
+const (
+	DTMODE_HOMOGENOUS = ""         // moments every Dt
+	DTMODE_DISCRETE   = "DISCRETE" // moments listed in Times
+)
+
+// Boundary semantics of intervals.
+// Comparison and exclusion operations treat touching
+// intervals as not overlapping, which is BOUNDS_CLOSED_OPEN.
+const (
+	BOUNDS_CLOSED_OPEN = "[)" // [Ts, Te): Ts inclusive, Te exclusive
+	BOUNDS_CLOSED      = "[]" // [Ts, Te]: both inclusive, touching intervals overlap at a point
+)
+
+// Allen relations of one interval to another.
+const (
+	RELATION_BEFORE        = "before"        // [this] [other]
+	RELATION_MEETS         = "meets"         // [this][other]
+	RELATION_OVERLAPS      = "overlaps"      // this starts first, ends inside other
+	RELATION_STARTS        = "starts"        // same start, this ends first
+	RELATION_DURING        = "during"        // this strictly inside other
+	RELATION_FINISHES      = "finishes"      // same end, this starts later
+	RELATION_EQUALS        = "equals"        // same start and end
+	RELATION_FINISHED_BY   = "finished-by"   // same end, this starts first
+	RELATION_CONTAINS      = "contains"      // other strictly inside this
+	RELATION_STARTED_BY    = "started-by"    // same start, other ends first
+	RELATION_OVERLAPPED_BY = "overlapped-by" // other starts first, ends inside this
+	RELATION_MET_BY        = "met-by"        // [other][this]
+	RELATION_AFTER         = "after"         // [other] [this]
+)
+
+// Temporal join modes.
+const (
+	JOIN_INNER = "INNER" // matched pairs only
+	JOIN_LEFT  = "LEFT"  // matched pairs and unmatched left intervals
+	JOIN_ANTI  = "ANTI"  // unmatched left intervals only
+)
+
+// Assignment modes of points to intervals.
+const (
+	ASSIGN_COVERING = "COVERING" // interval containing point
+	ASSIGN_ASOF     = "ASOF"     // interval containing point or latest ended before it
+	ASSIGN_NEAREST  = "NEAREST"  // interval containing point or closest one
+)
+
+// Kinds of interval changes between schedule versions.
+const (
+	DIFF_ADDED     = "added"     // interval exists only in new version
+	DIFF_REMOVED   = "removed"   // interval exists only in old version
+	DIFF_MOVED     = "moved"     // different start
+	DIFF_EXTENDED  = "extended"  // longer than before
+	DIFF_SHORTENED = "shortened" // shorter than before
+	DIFF_RENAMED   = "renamed"   // different name, matched by key
+)
+
+// Invariants reported by validation.
+const (
+	INVALID_ZERO_TIME = "zeroTime" // Ts or Te is not set
+	INVALID_ORDER     = "order"    // Te before Ts
+	INVALID_DT        = "dt"       // Dt is negative, or not positive in homogenous mode
+	INVALID_DTMODE    = "dtMode"   // unknown DtMode
+	INVALID_TIMES     = "times"    // Times outside of [Ts, Te]
+	INVALID_SORT      = "sort"     // intervals not sorted by Ts
+	INVALID_OVERLAP   = "overlap"  // intervals overlap where disallowed
+)
+
+// Optional validation rules.
+const (
+	VALIDATE_NO_OVERLAP    = "noOverlap"   // intervals must not overlap
+	VALIDATE_ALLOW_ZERO_DT = "allowZeroDt" // zero Dt accepted in homogenous mode
+)
+
+// Default separators of time interval string.
+const (
+	PARSE_SEP_INTERVAL = " --- "
+	PARSE_SEP_DURATION = " @ "
+)
+
+// iCalendar export and import.
+const (
+	ICS_PRODID       = "-//deze333//intvl//EN"
+	ICS_LAYOUT_UTC   = "20060102T150405Z"
+	ICS_LAYOUT_LOCAL = "20060102T150405"
+	ICS_LAYOUT_DATE  = "20060102"
+	ICS_LINE_MAX     = 75   // octets per line before folding
+	ICS_RECUR_MAX    = 1000 // occurrences expanded from unbounded RRULE
+)
+
+// Fields of time interval string that may fail to parse.
+const (
+	PARSE_FIELD_INTERVAL = "interval" // structure of string as a whole
+	PARSE_FIELD_START    = "start"
+	PARSE_FIELD_END      = "end"
+	PARSE_FIELD_DURATION = "duration"
+)
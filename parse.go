@@ -11,79 +11,70 @@ import (
 )
 
 //------------------------------------------------------------
-// Time Interval parsing functions
+// Parse errors
 //------------------------------------------------------------
 
-// Parses time interval from supplied string.
-// "layout --- layout"
-// "layout --- layout @ dt" where dt is like 15m, 7h, 30d
-func Parse_TimeInterval(layout, str string) (ti *TimeInterval, err error) {
+// ParseError describes failure to parse one time interval string.
+type ParseError struct {
+	Input string // string being parsed
+	Index int    // position of string among parsed strings
+	Field string // one of PARSE_FIELD_*
+	Err   error
+}
 
-	var dt time.Duration
-
-	// Extract duration
-	if strings.Index(str, "@") != -1 {
-		strs := strings.Split(str, " @ ")
-		if strs[1] != "" {
-			dt, err = xparam.StringToDuration(strs[1])
-			if err != nil {
-				err = errors.New("Unparseable duration: " + err.Error())
-				return
-			}
-			str = strs[0]
-		}
-	}
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Unparseable time interval at position %v, %v of %q: %v",
+		e.Index, e.Field, e.Input, e.Err)
+}
 
-	// Process start/end time
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
 
-	// Split into strings
-	strs := strings.Split(str, " --- ")
-	if len(strs) != 2 {
-		err = errors.New("Unparseable string, doesn't match 'layout --- layout': " + str)
-		return
-	}
+// ParseErrors collects failures of all strings that failed to parse.
+type ParseErrors []*ParseError
 
-	// Parse time start
-	var ts, te time.Time
-	ts, err = time.Parse(layout, strs[0])
-	if err != nil {
-		return
+func (errs ParseErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
 	}
+	return strings.Join(msgs, "; ")
+}
 
-	// Parse time end
-	te, err = time.Parse(layout, strs[1])
-	if err != nil {
-		return
+func (errs ParseErrors) Unwrap() []error {
+	res := make([]error, len(errs))
+	for i, e := range errs {
+		res[i] = e
 	}
+	return res
+}
 
-	// Verify ts < te
-	if !ts.Before(te) {
-		err = errors.New("Invalid TimeInterval: Ts must be before Te")
-		return
-	}
+//------------------------------------------------------------
+// Time Interval parsing functions
+//------------------------------------------------------------
 
-	ti = &TimeInterval{Ts: ts, Te: te, Dt: dt}
-	return
+// Parses time interval from supplied string.
+// "layout --- layout"
+// "layout --- layout @ dt" where dt is like 15m, 7h, 30d
+// Returns *ParseError on failure.
+func Parse_TimeInterval(layout, str string) (ti *TimeInterval, err error) {
+	return layoutParser(layout).Parse(str)
 }
 
 // Parses into array of time intervals from supplied strings.
 // "layout --- layout", "layout --- layout", ...
+// Stops at first failure and returns it as *ParseError.
 func ParseMany_TimeInterval(layout string, strs ...string) (tis []*TimeInterval, err error) {
+	return layoutParser(layout).ParseMany(strs...)
+}
 
-	var ti *TimeInterval
-	for i, str := range strs {
-
-		if ti, err = Parse_TimeInterval(layout, str); err == nil {
-			tis = append(tis, ti)
-		} else {
-			err = fmt.Errorf(
-				"Unparseable time interval at position %v: %v",
-				i, err)
-			return
-		}
-	}
-
-	return
+// Parses into array of time intervals from supplied strings.
+// "layout --- layout", "layout --- layout", ...
+// Unlike ParseMany_TimeInterval doesn't stop at failures:
+// returns all parsed intervals and ParseErrors for the rest.
+func ParseAll_TimeInterval(layout string, strs ...string) (tis []*TimeInterval, err error) {
+	return layoutParser(layout).ParseAll(strs...)
 }
 
 // Parses into array of time intervals from supplied strings.
@@ -99,3 +90,41 @@ func Parse_TimeIntervals(layout string, strs ...string) (tis TimeIntervals, err
 	tis = NewTimeIntervals(tiis...)
 	return
 }
+
+// Parses into array of time intervals from supplied strings
+// collecting all failures, see ParseAll_TimeInterval.
+func ParseAll_TimeIntervals(layout string, strs ...string) (tis TimeIntervals, err error) {
+
+	var tiis []*TimeInterval
+	tiis, err = ParseAll_TimeInterval(layout, strs...)
+	tis = NewTimeIntervals(tiis...)
+	return
+}
+
+// Parser for single layout with default separators.
+// Parses times with time.Parse.
+func layoutParser(layout string) *Parser {
+	return &Parser{
+		SepInterval: PARSE_SEP_INTERVAL,
+		SepDuration: PARSE_SEP_DURATION,
+		Layouts:     []string{layout},
+	}
+}
+
+//------------------------------------------------------------
+// Helpers
+//------------------------------------------------------------
+
+// Parses duration either in Go notation like 1h30m
+// or in short notation like 15m, 7h, 30d.
+func parseDuration(str string) (dt time.Duration, err error) {
+
+	if dt, err = time.ParseDuration(str); err == nil {
+		return
+	}
+
+	if dt, err = xparam.StringToDuration(str); err != nil {
+		err = errors.New("Unparseable duration: " + err.Error())
+	}
+	return
+}
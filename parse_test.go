@@ -0,0 +1,180 @@
+package intvl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+// Tests for parsing
+//------------------------------------------------------------
+
+// Tests parse errors.
+func TestParse_Errors(t *testing.T) {
+
+	layout := "2006 Jan 2 15:04:05 MST"
+
+	strs := []string{
+		"2014 Dec 14 20:00:00 UTC --- 2014 Dec 16 04:00:00 UTC",
+		"2014 Dec 15 04:00:00 UTC --- 2014 Dec 15 08:00:00 UTC@2h",
+		"2014 Dec 15 08:00:00 UTC --- 2014 Dex 15 16:00:00 UTC",
+		"2014 Dec 15 16:00:00 UTC --- 2014 Dec 15 20:00:00 UTC @ 15m",
+		"2014 Dec 15 20:00:00 UTC -- 2014 Dec 16 00:00:00 UTC",
+	}
+
+	// Stops at first failure, no panic on malformed duration
+	_, err := ParseMany_TimeInterval(layout, strs...)
+	fmt.Println(err)
+
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Index != 1 || perr.Field != PARSE_FIELD_DURATION {
+		t.Error("ParseMany must fail with ParseError at position 1:", err)
+	}
+
+	// Collects all failures
+	tis, err := ParseAll_TimeInterval(layout, strs...)
+	fmt.Println(err)
+
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 3 || len(tis) != 2 {
+		t.Fatal("ParseAll must collect all failures:", err)
+	}
+
+	if errs[1].Index != 2 || errs[1].Field != PARSE_FIELD_END {
+		t.Error("ParseAll must report end failure at position 2:", errs[1])
+	}
+
+	if errs[2].Index != 4 || errs[2].Field != PARSE_FIELD_INTERVAL {
+		t.Error("ParseAll must report separator failure at position 4:", errs[2])
+	}
+
+	// errors.As finds first failure among collected
+	if !errors.As(err, &perr) || perr.Index != 1 {
+		t.Error("ParseErrors must unwrap to ParseError")
+	}
+}
+
+// Tests configurable parser.
+func TestParser(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	// Custom separators and layouts
+	p := NewParser("02.01.2006 15:04", "02.01.2006")
+	p.SepInterval = " / "
+	p.SepDuration = " every "
+
+	ti, err := p.Parse("15.03.2015 12:00 / 16.03.2015 every 30m")
+	fmt.Println(ti, err)
+	if err != nil || !ti.Ts.Equal(t0) || !ti.Te.Equal(t0.Add(12*time.Hour)) || ti.Dt != 30*time.Minute {
+		t.Error("Parser failed on custom separators")
+	}
+
+	// Auto-detection
+	p = NewParser()
+	strs := []string{
+		"2015-03-15T12:00:00Z --- 2015-03-15T14:00:00+01:00",
+		"2015-03-15 12:00 --- 2015-03-16",
+		"1426420800 --- 1426424400.5",
+	}
+
+	tis, err := p.ParseMany(strs...)
+	fmt.Println(NewTimeIntervals(tis...), err)
+	if err != nil || len(tis) != 3 {
+		t.Fatal("Parser failed to auto-detect layouts:", err)
+	}
+	if !tis[0].Ts.Equal(t0) || !tis[0].Te.Equal(t0.Add(time.Hour)) {
+		t.Error("Parser failed on RFC3339")
+	}
+	if !tis[2].Ts.Equal(t0) || tis[2].Len() != time.Hour+500*time.Millisecond {
+		t.Error("Parser failed on Unix seconds")
+	}
+
+	// User layout error kept with auto-detection
+	p = NewParser("02.01.2006")
+	if _, err := p.ParseTime("15/03/2015"); err == nil || !strings.Contains(err.Error(), "02.01.2006") {
+		t.Error("Parser must report error of user layout:", err)
+	}
+
+	// Zero value has default separators and auto-detects
+	ti, err = (&Parser{}).Parse("2015-03-15T12:00:00Z --- 2015-03-15T13:00:00Z @ 15m")
+	if err != nil || !ti.Ts.Equal(t0) || ti.Dt != 15*time.Minute {
+		t.Error("Zero value Parser failed:", ti, err)
+	}
+
+		// Location
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("No time zone database:", err)
+	}
+
+	p = NewParser()
+	p.Location = berlin
+	ti, err = p.Parse("2015-03-15 13:00 --- 2015-03-15 14:00")
+	if err != nil || !ti.Ts.Equal(t0) {
+		t.Error("Parser failed to parse in location:", ti, err)
+	}
+}
+
+// Tests relative expressions.
+func TestParser_Expr(t *testing.T) {
+
+	// Wednesday
+	now := time.Date(2015, time.March, 18, 15, 30, 0, 0, time.UTC)
+
+	p := NewParser()
+	p.Now = func() time.Time { return now }
+
+	day := time.Date(2015, time.March, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr   string
+		ts, te time.Time
+		dt     time.Duration
+	}{
+		{"last 24h", now.Add(-24 * time.Hour), now, 0},
+		{"past 7d", now.AddDate(0, 0, -7), now, 0},
+		{"next 30m", now, now.Add(30 * time.Minute), 0},
+		{"today", day, day.AddDate(0, 0, 1), 0},
+		{"Yesterday @ 1h", day.AddDate(0, 0, -1), day, time.Hour},
+		{"this week", day.AddDate(0, 0, -2), day.AddDate(0, 0, 5), 0},
+		{"previous week", day.AddDate(0, 0, -9), day.AddDate(0, 0, -2), 0},
+		{"this month", day.AddDate(0, 0, -17), day.AddDate(0, 0, 14), 0},
+		{"previous month", day.AddDate(0, -1, -17), day.AddDate(0, 0, -17), 0},
+		{"next year", day.AddDate(1, -2, -17), day.AddDate(2, -2, -17), 0},
+		{"now-7d --- now", now.AddDate(0, 0, -7), now, 0},
+		{"today+9h --- today+17h30m @ 15m", day.Add(9 * time.Hour), day.Add(17*time.Hour + 30*time.Minute), 15 * time.Minute},
+		{"2015-03-01 --- now-1w", day.AddDate(0, 0, -17), now.AddDate(0, 0, -7), 0},
+	}
+
+	for _, test := range tests {
+		ti, err := p.ParseExpr(test.expr)
+		fmt.Println(test.expr, "=", ti, err)
+
+		if err != nil || !ti.Ts.Equal(test.ts) || !ti.Te.Equal(test.te) || ti.Dt != test.dt {
+			t.Error("ParseExpr failed:", test.expr, ti, err)
+		}
+	}
+
+	// Failures
+	for _, expr := range []string{"last", "this fortnight", "now*2 --- now", "now --- now-1h"} {
+		if _, err := p.ParseExpr(expr); err == nil {
+			t.Error("ParseExpr must fail:", expr)
+		}
+	}
+
+	// Calendar boundaries in location
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("No time zone database:", err)
+	}
+
+	p.Location = tokyo
+	ti, err := p.ParseExpr("today")
+	if err != nil || !ti.Ts.Equal(time.Date(2015, time.March, 19, 0, 0, 0, 0, tokyo)) {
+		t.Error("ParseExpr must resolve today in parser location:", ti, err)
+	}
+}
@@ -0,0 +1,303 @@
+// Configurable parser of time intervals.
+//
+//	"start --- end"
+//	"start --- end @ dt"
+//
+// Separators, location and time layouts are configurable.
+package intvl
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+// Auto-detection
+//------------------------------------------------------------
+
+// Layouts tried by auto-detection, in order.
+var autoLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	TIME_LAYOUT_DEBUG,
+}
+
+//------------------------------------------------------------
+// Parser model
+//------------------------------------------------------------
+
+// Parser parses time interval strings.
+// Each time is tried against Layouts in order and,
+// if AutoDetect is set or there are no Layouts, against
+// common layouts: RFC3339, date-time and date-only ISO 8601,
+// RFC1123 and Unix seconds like 1426420800 or 1426420800.5.
+// Zero value is ready to use: it has default separators
+// and auto-detects layouts.
+type Parser struct {
+	SepInterval string // separates start and end, " --- " if empty
+	SepDuration string // separates interval and dt, " @ " if empty
+	Layouts     []string
+	AutoDetect  bool
+
+	// Location of times without zone information.
+	// If nil, times are parsed with time.Parse.
+	Location *time.Location
+
+	// Clock and first day of week for relative expressions,
+	// see ParseExpr. If Now is nil, time.Now is used.
+	Now       func() time.Time
+	WeekStart time.Weekday
+}
+
+// NewParser creates parser with default separators,
+// given layouts and auto-detection enabled, parsing in UTC.
+// Weeks start on Monday.
+func NewParser(layouts ...string) *Parser {
+	return &Parser{
+		SepInterval: PARSE_SEP_INTERVAL,
+		SepDuration: PARSE_SEP_DURATION,
+		Layouts:     layouts,
+		AutoDetect:  true,
+		Location:    time.UTC,
+		WeekStart:   time.Monday,
+	}
+}
+
+//------------------------------------------------------------
+// Parser methods
+//------------------------------------------------------------
+
+// Parse parses single time interval string.
+// Returns *ParseError on failure.
+func (p *Parser) Parse(str string) (ti *TimeInterval, err error) {
+
+	var perr *ParseError
+	if ti, perr = p.parse(str, 0); perr != nil {
+		err = perr
+	}
+	return
+}
+
+// ParseMany parses time interval strings.
+// Stops at first failure and returns it as *ParseError.
+func (p *Parser) ParseMany(strs ...string) (tis []*TimeInterval, err error) {
+
+	for i, str := range strs {
+
+		ti, perr := p.parse(str, i)
+		if perr != nil {
+			err = perr
+			return
+		}
+		tis = append(tis, ti)
+	}
+
+	return
+}
+
+// ParseAll parses time interval strings.
+// Returns all parsed intervals and ParseErrors for the rest.
+func (p *Parser) ParseAll(strs ...string) (tis []*TimeInterval, err error) {
+
+	var errs ParseErrors
+	for i, str := range strs {
+
+		ti, perr := p.parse(str, i)
+		if perr != nil {
+			errs = append(errs, perr)
+			continue
+		}
+		tis = append(tis, ti)
+	}
+
+	if len(errs) != 0 {
+		err = errs
+	}
+	return
+}
+
+// ParseTime parses single time trying all layouts.
+func (p *Parser) ParseTime(str string) (t time.Time, err error) {
+
+	str = strings.TrimSpace(str)
+	detect := p.AutoDetect || len(p.Layouts) == 0
+
+	// First error is most relevant for user supplied layouts
+	var first error
+	try := func(layout string) bool {
+		var e error
+		if p.Location == nil {
+			t, e = time.Parse(layout, str)
+		} else {
+			t, e = time.ParseInLocation(layout, str, p.Location)
+		}
+		if e != nil && first == nil {
+			first = e
+		}
+		return e == nil
+	}
+
+	for _, layout := range p.Layouts {
+		if try(layout) {
+			return
+		}
+	}
+
+	if detect {
+		if unix, e := p.parseUnix(str); e == nil {
+			t = unix
+			return
+		}
+		for _, layout := range autoLayouts {
+			if try(layout) {
+				return
+			}
+		}
+	}
+
+	if len(p.Layouts) != 0 {
+		err = first
+	} else {
+		err = errors.New("Time doesn't match any known layout: " + str)
+	}
+	return
+}
+
+// Parses Unix seconds with optional fraction.
+func (p *Parser) parseUnix(str string) (t time.Time, err error) {
+
+	secs, frac := str, ""
+	if i := strings.Index(str, "."); i != -1 {
+		secs, frac = str[:i], str[i+1:]
+	}
+
+	var sec, nsec int64
+	if sec, err = strconv.ParseInt(secs, 10, 64); err != nil {
+		return
+	}
+
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		if nsec, err = strconv.ParseInt(frac, 10, 64); err != nil || nsec < 0 {
+			err = errors.New("Unparseable Unix time: " + str)
+			return
+		}
+		for i := len(frac); i < 9; i++ {
+			nsec *= 10
+		}
+		if sec < 0 || strings.HasPrefix(secs, "-") {
+			nsec = -nsec
+		}
+	}
+
+	t = time.Unix(sec, nsec)
+	if p.Location != nil {
+		t = t.In(p.Location)
+	} else {
+		t = t.UTC()
+	}
+	return
+}
+
+// Parses single time interval string at position idx.
+func (p *Parser) parse(str string, idx int) (ti *TimeInterval, perr *ParseError) {
+
+	fail := func(field string, err error) *ParseError {
+		return &ParseError{Input: str, Index: idx, Field: field, Err: err}
+	}
+
+	input, dt, err := p.splitDuration(str)
+	if err != nil {
+		perr = fail(PARSE_FIELD_DURATION, err)
+		return
+	}
+
+	// Process start/end time
+
+	// Split into strings
+	strs := strings.Split(input, p.sepInterval())
+	if len(strs) != 2 {
+		perr = fail(PARSE_FIELD_INTERVAL, errors.New(
+			"String doesn't match 'start"+p.sepInterval()+"end'"))
+		return
+	}
+
+	// Parse time start
+	var ts, te time.Time
+	if ts, err = p.ParseTime(strs[0]); err != nil {
+		perr = fail(PARSE_FIELD_START, err)
+		return
+	}
+
+	// Parse time end
+	if te, err = p.ParseTime(strs[1]); err != nil {
+		perr = fail(PARSE_FIELD_END, err)
+		return
+	}
+
+	// Verify ts < te
+	if !ts.Before(te) {
+		perr = fail(PARSE_FIELD_INTERVAL,
+			errors.New("Invalid TimeInterval: Ts must be before Te"))
+		return
+	}
+
+	ti = &TimeInterval{Ts: ts, Te: te, Dt: dt}
+	return
+}
+
+// Splits string into interval part and duration
+// that follows duration separator, if any.
+func (p *Parser) splitDuration(str string) (input string, dt time.Duration, err error) {
+
+	input = str
+
+	mark := strings.TrimSpace(p.sepDuration())
+	if mark == "" || !strings.Contains(str, mark) {
+		return
+	}
+
+	strs := strings.Split(str, p.sepDuration())
+	if len(strs) != 2 {
+		err = errors.New(
+			"String doesn't match 'start" + p.sepInterval() + "end" + p.sepDuration() + "dt'")
+		return
+	}
+	if strs[1] == "" {
+		err = errors.New("Empty duration")
+		return
+	}
+	if dt, err = parseDuration(strs[1]); err != nil {
+		return
+	}
+
+	input = strs[0]
+	return
+}
+
+// Returns separator of start and end.
+func (p *Parser) sepInterval() string {
+	if p.SepInterval == "" {
+		return PARSE_SEP_INTERVAL
+	}
+	return p.SepInterval
+}
+
+// Returns separator of interval and dt.
+func (p *Parser) sepDuration() string {
+	if p.SepDuration == "" {
+		return PARSE_SEP_DURATION
+	}
+	return p.SepDuration
+}
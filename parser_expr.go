@@ -0,0 +1,303 @@
+// Relative and natural-language time interval expressions.
+//
+//	"last 24h", "next 2h"
+//	"today", "yesterday", "tomorrow"
+//	"this week", "previous month", "next year"
+//	"now-7d --- now", "today+9h --- today+17h"
+//	"yesterday @ 1h"
+//
+// Expressions are resolved against parser's clock and location.
+package intvl
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+// Relative expressions
+//------------------------------------------------------------
+
+// ParseExpr parses relative time interval expression.
+//
+// Single word periods are full calendar days:
+// "today", "yesterday", "tomorrow".
+//
+// Calendar periods are "<which> <unit>" where which is
+// "this" (or "current"), "previous" (or "last") and "next",
+// and unit is "hour", "day", "week", "month" or "year".
+//
+// Durations relative to now are "last <dur>" (or "past <dur>")
+// and "next <dur>", for example "last 24h", "next 30m", "last 7d".
+//
+// Ranges are "start --- end" where each side is either a time
+// in one of parser layouts or an anchor "now", "today", "yesterday",
+// "tomorrow" followed by offsets like "now-7d", "today+9h30m".
+// Offsets in d and w units are calendar days and weeks.
+//
+// Any expression may be followed by duration suffix, " @ 1h".
+// Returns *ParseError on failure.
+func (p *Parser) ParseExpr(str string) (ti *TimeInterval, err error) {
+
+	var perr *ParseError
+	if ti, perr = p.parseExpr(str, 0); perr != nil {
+		err = perr
+	}
+	return
+}
+
+// ParseTimeExpr parses single time, either in one of
+// parser layouts or anchor with offsets like "now-7d".
+func (p *Parser) ParseTimeExpr(str string) (t time.Time, err error) {
+
+	expr := strings.ToLower(strings.TrimSpace(str))
+	now := p.now()
+
+	for _, anchor := range []string{"now", "today", "yesterday", "tomorrow"} {
+		if !strings.HasPrefix(expr, anchor) {
+			continue
+		}
+
+		switch anchor {
+		case "now":
+			t = now
+		case "today":
+			t = startOf("day", now, p.WeekStart)
+		case "yesterday":
+			t = startOf("day", now, p.WeekStart).AddDate(0, 0, -1)
+		case "tomorrow":
+			t = startOf("day", now, p.WeekStart).AddDate(0, 0, 1)
+		}
+
+		return addOffsets(t, expr[len(anchor):])
+	}
+
+	return p.ParseTime(str)
+}
+
+// Returns current time in parser location.
+func (p *Parser) now() time.Time {
+
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return now.In(loc)
+}
+
+// Parses single relative expression at position idx.
+func (p *Parser) parseExpr(str string, idx int) (ti *TimeInterval, perr *ParseError) {
+
+	fail := func(field string, err error) *ParseError {
+		return &ParseError{Input: str, Index: idx, Field: field, Err: err}
+	}
+
+	input, dt, err := p.splitDuration(str)
+	if err != nil {
+		perr = fail(PARSE_FIELD_DURATION, err)
+		return
+	}
+
+	var ts, te time.Time
+
+	if strs := strings.Split(input, p.sepInterval()); len(strs) == 2 {
+
+		// Range of two time expressions
+		if ts, err = p.ParseTimeExpr(strs[0]); err != nil {
+			perr = fail(PARSE_FIELD_START, err)
+			return
+		}
+		if te, err = p.ParseTimeExpr(strs[1]); err != nil {
+			perr = fail(PARSE_FIELD_END, err)
+			return
+		}
+
+	} else if ts, te, err = p.parsePeriod(input); err != nil {
+		perr = fail(PARSE_FIELD_INTERVAL, err)
+		return
+	}
+
+	// Verify ts < te
+	if !ts.Before(te) {
+		perr = fail(PARSE_FIELD_INTERVAL,
+			errors.New("Invalid TimeInterval: Ts must be before Te"))
+		return
+	}
+
+	ti = &TimeInterval{Ts: ts, Te: te, Dt: dt}
+	return
+}
+
+// Parses named period like "today" or "last 24h".
+func (p *Parser) parsePeriod(str string) (ts, te time.Time, err error) {
+
+	words := strings.Fields(strings.ToLower(str))
+	now := p.now()
+
+	// Single day
+	if len(words) == 1 {
+		day := startOf("day", now, p.WeekStart)
+		switch words[0] {
+		case "today":
+			return day, day.AddDate(0, 0, 1), nil
+		case "yesterday":
+			return day.AddDate(0, 0, -1), day, nil
+		case "tomorrow":
+			return day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), nil
+		}
+	}
+
+	if len(words) != 2 {
+		err = errors.New("Unknown time interval expression: " + str)
+		return
+	}
+
+	which, unit := words[0], words[1]
+
+	// Calendar period
+	if isCalendarUnit(unit) {
+		start := startOf(unit, now, p.WeekStart)
+		switch which {
+		case "this", "current":
+			return start, addUnits(unit, start, 1), nil
+		case "previous", "last", "past":
+			return addUnits(unit, start, -1), start, nil
+		case "next":
+			return addUnits(unit, start, 1), addUnits(unit, start, 2), nil
+		}
+		err = errors.New("Unknown calendar period: " + str)
+		return
+	}
+
+	// Duration relative to now
+	switch which {
+	case "last", "past":
+		if ts, err = addOffsets(now, "-"+unit); err != nil {
+			return
+		}
+		return ts, now, nil
+	case "next":
+		if te, err = addOffsets(now, "+"+unit); err != nil {
+			return
+		}
+		return now, te, nil
+	}
+
+	err = errors.New("Unknown time interval expression: " + str)
+	return
+}
+
+//------------------------------------------------------------
+// Calendar helpers
+//------------------------------------------------------------
+
+// Checks if unit is a calendar period name.
+func isCalendarUnit(unit string) bool {
+	switch unit {
+	case "hour", "day", "week", "month", "year":
+		return true
+	}
+	return false
+}
+
+// Returns start of calendar period containing t, in t's location.
+func startOf(unit string, t time.Time, weekStart time.Weekday) time.Time {
+
+	y, m, d := t.Date()
+	loc := t.Location()
+
+	switch unit {
+	case "hour":
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, loc)
+	case "week":
+		back := (int(t.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(y, m, d-back, 0, 0, 0, 0, loc)
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// Adds n calendar periods to t.
+func addUnits(unit string, t time.Time, n int) time.Time {
+
+	switch unit {
+	case "hour":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "year":
+		return t.AddDate(n, 0, 0)
+	}
+
+	return t.AddDate(0, 0, n)
+}
+
+// Applies offsets like "-7d+2h" to t.
+// Offsets in d and w units are calendar days and weeks,
+// others are parsed as durations.
+func addOffsets(t time.Time, offsets string) (res time.Time, err error) {
+
+	res = t
+	str := strings.TrimSpace(offsets)
+
+	for str != "" {
+
+		sign := 1
+		switch str[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			err = errors.New("Offset must start with + or -: " + str)
+			return
+		}
+		str = strings.TrimSpace(str[1:])
+
+		// Offset runs until next sign
+		end := strings.IndexAny(str, "+-")
+		if end == -1 {
+			end = len(str)
+		}
+		offset := strings.TrimSpace(str[:end])
+		str = strings.TrimSpace(str[end:])
+
+		if offset == "" {
+			err = errors.New("Empty offset")
+			return
+		}
+
+		// Calendar days and weeks
+		last := offset[len(offset)-1]
+		if last == 'd' || last == 'w' {
+			if n, e := strconv.Atoi(offset[:len(offset)-1]); e == nil {
+				if last == 'w' {
+					n *= 7
+				}
+				res = res.AddDate(0, 0, sign*n)
+				continue
+			}
+		}
+
+		var dur time.Duration
+		if dur, err = parseDuration(offset); err != nil {
+			return
+		}
+		res = res.Add(time.Duration(sign) * dur)
+	}
+
+	return
+}
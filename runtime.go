@@ -1,15 +1,35 @@
 // Package level settings.
 package intvl
 
+import (
+	"sync"
+	"time"
+)
+
 //------------------------------------------------------------
 // Runtime model & instance
 //------------------------------------------------------------
 
+// Runtime holds settings for parsing, rendering and analysis.
+// Functions without explicit runtime use package default,
+// see DefaultRuntime and SetDefaultRuntime.
 type Runtime struct {
-	timeLayoutDebug string
+	TimeLayoutDebug string         // layout of times in String and Dump
+	Location        *time.Location // location of parsed times without zone
+	Boundary        string         // one of BOUNDS_*, used by overlap checks, exclusion and analysis
+	Tolerance       time.Duration  // gaps and overlaps up to this length are ignored
+
+	// Location of times in String and Dump.
+	// If nil, each time is shown in its own location.
+	DisplayLocation *time.Location
 }
 
-var _runtime *Runtime
+// Package default runtime.
+// Installed instance is never modified, setters replace it.
+var (
+	_runtime   *Runtime
+	_runtimeMu sync.RWMutex
+)
 
 //------------------------------------------------------------
 // Initialize
@@ -17,16 +37,125 @@ var _runtime *Runtime
 
 // Initializes package runtime.
 func init() {
-	_runtime = &Runtime{
-		timeLayoutDebug: TIME_LAYOUT_DEBUG,
+	_runtime = NewRuntime()
+}
+
+// NewRuntime creates runtime with default settings.
+func NewRuntime() *Runtime {
+	return &Runtime{
+		TimeLayoutDebug: TIME_LAYOUT_DEBUG,
+		Location:        time.UTC,
+		Boundary:        BOUNDS_CLOSED_OPEN,
+		DisplayLocation: time.UTC,
 	}
 }
 
+// Clone produces copy of runtime.
+func (rt *Runtime) Clone() *Runtime {
+	clone := *rt
+	return &clone
+}
+
+//------------------------------------------------------------
+// Package default
+//------------------------------------------------------------
+
+// DefaultRuntime returns copy of package default runtime.
+func DefaultRuntime() *Runtime {
+	return defaultRuntime().Clone()
+}
+
+// SetDefaultRuntime replaces package default runtime with copy of rt.
+func SetDefaultRuntime(rt *Runtime) {
+	clone := rt.Clone()
+
+	_runtimeMu.Lock()
+	_runtime = clone
+	_runtimeMu.Unlock()
+}
+
+// Returns package default runtime for reading only.
+func defaultRuntime() *Runtime {
+	_runtimeMu.RLock()
+	rt := _runtime
+	_runtimeMu.RUnlock()
+	return rt
+}
+
 //------------------------------------------------------------
 // Setters
 //------------------------------------------------------------
 
 // Sets current runtime time output format.
 func Runtime_TimeLayout_Debug(layout string) {
-	_runtime.timeLayoutDebug = layout
+	_runtimeMu.Lock()
+	rt := _runtime.Clone()
+	rt.TimeLayoutDebug = layout
+	_runtime = rt
+	_runtimeMu.Unlock()
+}
+
+//------------------------------------------------------------
+// Runtime methods
+//------------------------------------------------------------
+
+// Parser creates parser using runtime location.
+// Debug layout is tried after given layouts,
+// so that output of String can be parsed back.
+func (rt *Runtime) Parser(layouts ...string) *Parser {
+	p := NewParser(append(layouts, rt.TimeLayoutDebug)...)
+	p.Location = rt.location()
+	return p
+}
+
+// IsOverlap checks if intervals share any time
+// according to boundary semantics.
+func (rt *Runtime) IsOverlap(a, b *TimeInterval) bool {
+	if rt.Boundary == BOUNDS_CLOSED {
+		return !a.Ts.After(b.Te) && !b.Ts.After(a.Te)
+	}
+	return a.Ts.Before(b.Te) && b.Ts.Before(a.Te)
+}
+
+// Contains checks if t lies within interval
+// according to boundary semantics.
+func (rt *Runtime) Contains(ti *TimeInterval, t time.Time) bool {
+	if t.Before(ti.Ts) {
+		return false
+	}
+	if rt.Boundary == BOUNDS_CLOSED {
+		return !t.After(ti.Te)
+	}
+	return t.Before(ti.Te)
+}
+
+// Checks if interval ended before t
+// according to boundary semantics.
+func (rt *Runtime) isEndedBy(ti *TimeInterval, t time.Time) bool {
+	return rt.isEndBefore(ti.Te, t)
+}
+
+// Checks if interval ending at te ended before t
+// according to boundary semantics.
+func (rt *Runtime) isEndBefore(te, t time.Time) bool {
+	if rt.Boundary == BOUNDS_CLOSED {
+		return te.Before(t)
+	}
+	return !te.After(t)
+}
+
+// Converts time to display location.
+func (rt *Runtime) display(t time.Time) time.Time {
+	if rt.DisplayLocation == nil {
+		return t
+	}
+	return t.In(rt.DisplayLocation)
+}
+
+// Returns runtime location or UTC if not set.
+func (rt *Runtime) location() *time.Location {
+	if rt.Location == nil {
+		return time.UTC
+	}
+	return rt.Location
 }
@@ -22,6 +22,12 @@ type TimeInterval struct {
 	// Meta information
 	Name string `bson:"name,omitempty"     json:"name,omitempty"`
 
+	// Load carried by interval, such as CPU cores or seats
+	Weight float64 `bson:"weight,omitempty"   json:"weight,omitempty"`
+
+	// User payload, see PayloadPolicy
+	Data interface{} `bson:"data,omitempty"     json:"data,omitempty"`
+
 	// Not exposed
 	ts *time.Time
 	te *time.Time
@@ -29,6 +35,9 @@ type TimeInterval struct {
 	// TimeIntervals inner index
 	idx int
 
+	// Point index of TimeIntervals starting with this interval
+	index *pointIndex
+
 	// Gap analysis data
 	isGap          bool
 	isGapInner     bool
@@ -39,6 +48,62 @@ type TimeInterval struct {
 	// NOTE When adding/renaming fields, don't forget to update .Clone()
 }
 
+//------------------------------------------------------------
+// Constructor
+//------------------------------------------------------------
+
+// TimeIntervalOption sets optional field of new interval.
+type TimeIntervalOption func(ti *TimeInterval)
+
+// WithDt sets interval granularity.
+func WithDt(dt time.Duration) TimeIntervalOption {
+	return func(ti *TimeInterval) { ti.Dt = dt }
+}
+
+// WithDtMode sets interval distribution mode.
+func WithDtMode(mode string) TimeIntervalOption {
+	return func(ti *TimeInterval) { ti.DtMode = mode }
+}
+
+// WithTimes sets discrete moments of interval.
+func WithTimes(times ...time.Time) TimeIntervalOption {
+	return func(ti *TimeInterval) {
+		ti.Times = make([]time.Time, len(times))
+		copy(ti.Times, times)
+	}
+}
+
+// WithName sets interval name.
+func WithName(name string) TimeIntervalOption {
+	return func(ti *TimeInterval) { ti.Name = name }
+}
+
+// WithData sets interval payload.
+func WithData(data interface{}) TimeIntervalOption {
+	return func(ti *TimeInterval) { ti.Data = data }
+}
+
+// WithWeight sets interval load.
+func WithWeight(weight float64) TimeIntervalOption {
+	return func(ti *TimeInterval) { ti.Weight = weight }
+}
+
+// NewTimeInterval creates interval [ts, te] and validates it.
+// Returns ValidationErrors if interval is invalid, see Validate.
+func NewTimeInterval(ts, te time.Time, opts ...TimeIntervalOption) (*TimeInterval, error) {
+
+	ti := &TimeInterval{Ts: ts, Te: te}
+	for _, opt := range opts {
+		opt(ti)
+	}
+
+	if err := ti.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ti, nil
+}
+
 //------------------------------------------------------------
 // Methods
 //------------------------------------------------------------
@@ -52,6 +117,8 @@ func (ti *TimeInterval) Clone() *TimeInterval {
 		Dt:             ti.Dt,
 		DtMode:         ti.DtMode,
 		Name:           ti.Name,
+		Weight:         ti.Weight,
+		Data:           ti.Data,
 		isGap:          ti.isGap,
 		isGapInner:     ti.isGapInner,
 		isGapLeft:      ti.isGapLeft,
@@ -0,0 +1,127 @@
+// Configurable comparison of time intervals.
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Comparator model
+//------------------------------------------------------------
+
+// Comparator decides which fields count when intervals are
+// compared and how far apart times may be to still be equal.
+type Comparator struct {
+	Tolerance time.Duration // maximum difference of equal times, e.g. clock skew
+
+	Name   bool
+	Dt     bool
+	DtMode bool
+	Times  bool
+}
+
+// NewComparator creates comparator of same fields as IsEqual:
+// Ts, Te, Dt and DtMode, with no tolerance.
+func NewComparator() *Comparator {
+	return &Comparator{
+		Dt:     true,
+		DtMode: true,
+	}
+}
+
+//------------------------------------------------------------
+// Comparator methods
+//------------------------------------------------------------
+
+// IsEqual compares intervals by chosen fields.
+func (c *Comparator) IsEqual(a, b *TimeInterval) bool {
+
+	switch {
+	case !c.isNear(a.Ts, b.Ts) || !c.isNear(a.Te, b.Te):
+		return false
+	case c.Name && a.Name != b.Name:
+		return false
+	case c.Dt && a.Dt != b.Dt:
+		return false
+	case c.DtMode && a.DtMode != b.DtMode:
+		return false
+	case c.Times && len(a.Times) != len(b.Times):
+		return false
+	}
+
+	if c.Times {
+		for i, t := range a.Times {
+			if !c.isNear(t, b.Times[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// IsEqual_TimeIntervals compares intervals one by one,
+// so both must have same order and fragmentation.
+func (c *Comparator) IsEqual_TimeIntervals(a, b TimeIntervals) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !c.IsEqual(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsEqual_Coverage compares time covered by intervals,
+// regardless of order and fragmentation. Other fields
+// are ignored, gaps not longer than tolerance are treated
+// as covered and boundaries of covered time are compared
+// within tolerance.
+func (c *Comparator) IsEqual_Coverage(a, b TimeIntervals) bool {
+
+	ca := c.bridge(NewIntervalSet(a...))
+	cb := c.bridge(NewIntervalSet(b...))
+
+	if len(ca) != len(cb) {
+		return false
+	}
+
+	for i, ti := range ca {
+		if !c.isNear(ti.Ts, cb[i].Ts) || !c.isNear(ti.Te, cb[i].Te) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns covered time of set with gaps not longer
+// than tolerance joined.
+func (c *Comparator) bridge(s IntervalSet) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range s.tis {
+
+		if n := len(res); n != 0 && ti.Ts.Sub(res[n-1].Te) <= c.Tolerance {
+			res[n-1].Te = ti.Te
+			continue
+		}
+
+		res = append(res, &TimeInterval{Ts: ti.Ts, Te: ti.Te})
+	}
+
+	return
+}
+
+// Checks if times differ by no more than tolerance.
+func (c *Comparator) isNear(a, b time.Time) bool {
+
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d <= c.Tolerance
+}
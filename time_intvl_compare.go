@@ -1,6 +1,8 @@
 // Comparison of time intervals.
 package intvl
 
+import "time"
+
 //------------------------------------------------------------
 // Time Interval comparison
 //------------------------------------------------------------
@@ -119,3 +121,45 @@ func (this *TimeInterval) IsRightAdjacent(other *TimeInterval) bool {
 
 	return other.Te.Equal(this.Ts)
 }
+
+// Relation returns Allen relation of this interval
+// to other, one of RELATION_*.
+//     [   this   ]
+//            [ other ]    overlaps
+func (this *TimeInterval) Relation(other *TimeInterval) string {
+
+	switch {
+	case this.IsEqual_TsTe(other):
+		return RELATION_EQUALS
+	case this.Te.Before(other.Ts):
+		return RELATION_BEFORE
+	case other.Te.Before(this.Ts):
+		return RELATION_AFTER
+	case this.Te.Equal(other.Ts):
+		return RELATION_MEETS
+	case other.Te.Equal(this.Ts):
+		return RELATION_MET_BY
+	case this.Ts.Equal(other.Ts) && this.Te.Before(other.Te):
+		return RELATION_STARTS
+	case this.Ts.Equal(other.Ts):
+		return RELATION_STARTED_BY
+	case this.Te.Equal(other.Te) && this.Ts.After(other.Ts):
+		return RELATION_FINISHES
+	case this.Te.Equal(other.Te):
+		return RELATION_FINISHED_BY
+	case this.Ts.After(other.Ts) && this.Te.Before(other.Te):
+		return RELATION_DURING
+	case this.Ts.Before(other.Ts) && this.Te.After(other.Te):
+		return RELATION_CONTAINS
+	case this.Ts.Before(other.Ts):
+		return RELATION_OVERLAPS
+	default:
+		return RELATION_OVERLAPPED_BY
+	}
+}
+
+// IsEqual_Tolerance checks if [Ts:Te) of both intervals
+// are equal within tolerance, such as clock skew.
+func (ti *TimeInterval) IsEqual_Tolerance(other *TimeInterval, tolerance time.Duration) bool {
+	return (&Comparator{Tolerance: tolerance}).IsEqual(ti, other)
+}
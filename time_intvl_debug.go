@@ -30,16 +30,27 @@ func (ti *TimeInterval) LenHuman() string {
 
 // String converts interval to string.
 func (ti *TimeInterval) String() string {
-	return dumpToStringLine("TimeInterval", ti.Dump())
+	return ti.String_Runtime(defaultRuntime())
+}
+
+// String_Runtime converts interval to string using runtime settings.
+func (ti *TimeInterval) String_Runtime(rt *Runtime) string {
+	return dumpToStringLine("TimeInterval", ti.Dump_Runtime(rt))
 }
 
 // Dump provides raw kv array of fields and their values.
 func (ti *TimeInterval) Dump() []interface{} {
+	return ti.Dump_Runtime(defaultRuntime())
+}
+
+// Dump_Runtime provides raw kv array of fields and their values
+// with times formatted using runtime settings.
+func (ti *TimeInterval) Dump_Runtime(rt *Runtime) []interface{} {
 
 	dump := []interface{}{
 		fmt.Sprintf("[%v --- %v] dt = %v len = %v",
-			ti.Ts.UTC().Format(_runtime.timeLayoutDebug),
-			ti.Te.UTC().Format(_runtime.timeLayoutDebug),
+			rt.display(ti.Ts).Format(rt.TimeLayoutDebug),
+			rt.display(ti.Te).Format(rt.TimeLayoutDebug),
 			ti.Dt, ti.Te.Sub(ti.Ts)),
 	}
 
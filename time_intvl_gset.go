@@ -1,26 +1,51 @@
 // Get/set operations of time interval.
 package intvl
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 //------------------------------------------------------------
 // Time Interval qualities
 //------------------------------------------------------------
 
 // Ts sets starting point of interval.
+// Panics if end set earlier is before t.
 func (ti *TimeInterval) Start(t time.Time) {
-	ti.ts = &t
-	if ti.te != nil && (*ti.te).Before(*ti.ts) {
-		panic("Invalid TimeInterval: end before start")
+	if err := ti.SetStart(t); err != nil {
+		panic(err.Error())
 	}
-	ti.Ts = t
 }
 
 // Ts sets starting point of interval.
+// Panics if start set earlier is after t.
 func (ti *TimeInterval) End(t time.Time) {
-	ti.te = &t
-	if ti.ts != nil && (*ti.te).Before(*ti.ts) {
-		panic("Invalid TimeInterval: end before start")
+	if err := ti.SetEnd(t); err != nil {
+		panic(err.Error())
+	}
+}
+
+// SetStart sets starting point of interval.
+// Fails and leaves interval unchanged
+// if end set earlier is before t.
+func (ti *TimeInterval) SetStart(t time.Time) error {
+	if ti.te != nil && (*ti.te).Before(t) {
+		return errors.New("Invalid TimeInterval: end before start")
 	}
+	ti.ts = &t
+	ti.Ts = t
+	return nil
+}
+
+// SetEnd sets ending point of interval.
+// Fails and leaves interval unchanged
+// if start set earlier is after t.
+func (ti *TimeInterval) SetEnd(t time.Time) error {
+	if ti.ts != nil && t.Before(*ti.ts) {
+		return errors.New("Invalid TimeInterval: end before start")
+	}
+	ti.te = &t
 	ti.Te = t
+	return nil
 }
@@ -14,17 +14,20 @@ import "time"
 //	     xxx
 // Result:
 // 	        [    ti     ]
+// Result keeps Name, Weight and Data of interval.
 func (this *TimeInterval) TrimLeft(t time.Time) (ti *TimeInterval) {
+	return this.trimLeft(nil, t)
+}
+
+// Trims interval from left using payload policy.
+func (this *TimeInterval) trimLeft(p *PayloadPolicy, t time.Time) (ti *TimeInterval) {
 
 	// t is outside of interval, nothing happens
 	if t.Sub(this.Ts) <= 0 || t.Sub(this.Te) >= 0 {
 		return this
 	}
 
-	ti = this.CloneMin()
-	ti.Ts = t
-
-	return
+	return p.cut(this, t, this.Te)
 }
 
 // Trims interval from left leaving what's on the left of t.
@@ -34,27 +37,42 @@ func (this *TimeInterval) TrimLeft(t time.Time) (ti *TimeInterval) {
 //	          xxxxxxxxxxxx
 // Result:
 // 	    [ ti ]
+// Result keeps Name, Weight and Data of interval.
 func (this *TimeInterval) TrimRight(t time.Time) (ti *TimeInterval) {
+	return this.trimRight(nil, t)
+}
+
+// Trims interval from right using payload policy.
+func (this *TimeInterval) trimRight(p *PayloadPolicy, t time.Time) (ti *TimeInterval) {
 
 	// t is outside of interval, nothing happens
 	if t.Sub(this.Ts) <= 0 || t.Sub(this.Te) >= 0 {
 		return this
 	}
 
-	ti = this.CloneMin()
-	ti.Te = t
-
-	return
+	return p.cut(this, this.Ts, t)
 }
 
 // Exclude excludes other interval.
+// Remaining parts keep Name, Weight and Data of interval.
 func (this *TimeInterval) Exclude(other *TimeInterval) []*TimeInterval {
+	return this.exclude(defaultRuntime(), nil, other)
+}
+
+// Exclude_Runtime excludes other interval
+// according to runtime boundary semantics.
+func (this *TimeInterval) Exclude_Runtime(rt *Runtime, other *TimeInterval) []*TimeInterval {
+	return this.exclude(rt, nil, other)
+}
+
+// Excludes other interval using runtime and payload policy.
+func (this *TimeInterval) exclude(rt *Runtime, p *PayloadPolicy, other *TimeInterval) []*TimeInterval {
 
 	res := []*TimeInterval{}
 
 	// No overlap at all
-	if other.IsBefore(this) || other.IsAfter(this) {
-		return append(res, this.CloneMin())
+	if !rt.IsOverlap(this, other) {
+		return append(res, p.cut(this, this.Ts, this.Te))
 	}
 
 	// Other fully covers
@@ -69,32 +87,18 @@ func (this *TimeInterval) Exclude(other *TimeInterval) []*TimeInterval {
 		case this.Ts.Equal(other.Ts):
 			// Both starts equal, only right gap
 
-			right := this.CloneMin()
-			right.Ts = other.Te
-			right.Te = this.Te
-			res = append(res, right)
+			res = append(res, p.cut(this, other.Te, this.Te))
 
 		case this.Te.Equal(other.Te):
 			// Both ends equal, only left gap
 
-			left := this.CloneMin()
-			left.Ts = this.Ts
-			left.Te = other.Ts
-			res = append(res, left)
+			res = append(res, p.cut(this, this.Ts, other.Ts))
 
 		default:
 			// Other padded from left and right
 
-			left := this.CloneMin()
-			left.Ts = this.Ts
-			left.Te = other.Ts
-
-			right := this.CloneMin()
-			right.Ts = other.Te
-			right.Te = this.Te
-
-			res = append(res, left)
-			res = append(res, right)
+			res = append(res, p.cut(this, this.Ts, other.Ts))
+			res = append(res, p.cut(this, other.Te, this.Te))
 		}
 
 		return res
@@ -103,20 +107,13 @@ func (this *TimeInterval) Exclude(other *TimeInterval) []*TimeInterval {
 	// Other ends inside
 	if other.IsEndsInside(this) {
 
-		ti := this.CloneMin()
-		ti.Ts = other.Te
-
-		return append(res, ti)
+		return append(res, p.cut(this, other.Te, this.Te))
 	}
 
 	// Other starts inside
 	if other.IsStartsInside(this) {
 
-		ti := this.CloneMin()
-		ti.Ts = this.Ts
-		ti.Te = other.Ts
-
-		return append(res, ti)
+		return append(res, p.cut(this, this.Ts, other.Ts))
 	}
 
 	// Other
@@ -134,6 +131,12 @@ func (this *TimeInterval) Exclude(other *TimeInterval) []*TimeInterval {
 // 	Source:        [ ]
 // 	Result:        [ ]
 func (ti *TimeInterval) Split(dur time.Duration) (tis []*TimeInterval) {
+	return ti.splitBy(nil, dur)
+}
+
+// Splits interval into subintervals of len dur
+// using payload policy.
+func (ti *TimeInterval) splitBy(p *PayloadPolicy, dur time.Duration) (tis []*TimeInterval) {
 
 	if ti.Len() == 0 {
 		return
@@ -157,10 +160,7 @@ func (ti *TimeInterval) Split(dur time.Duration) (tis []*TimeInterval) {
 			te = ti.Te
 		}
 
-		sub := ti.Clone()
-		sub.Ts = ts
-		sub.Te = te
-		tis = append(tis, sub)
+		tis = append(tis, p.part(ti, ts, te))
 
 		ts = te
 	}
@@ -176,6 +176,11 @@ func (ti *TimeInterval) Split(dur time.Duration) (tis []*TimeInterval) {
 // 	Source:            [ |     |     ]
 // 	Result:        [ dur | dur | dur ]
 func (ti *TimeInterval) SplitExtend_Leftwards(dur time.Duration) (tis []*TimeInterval) {
+	return ti.splitExtendLeftwards(nil, dur)
+}
+
+// Splits interval leftwards using payload policy.
+func (ti *TimeInterval) splitExtendLeftwards(p *PayloadPolicy, dur time.Duration) (tis []*TimeInterval) {
 
 	if ti.Len() == 0 {
 		return
@@ -194,10 +199,7 @@ func (ti *TimeInterval) SplitExtend_Leftwards(dur time.Duration) (tis []*TimeInt
 
 		ts = te.Add(-dur)
 
-		sub := ti.Clone()
-		sub.Ts = ts
-		sub.Te = te
-		tis = append(tis, sub)
+		tis = append(tis, p.part(ti, ts, te))
 
 		te = ts
 	}
@@ -213,6 +215,11 @@ func (ti *TimeInterval) SplitExtend_Leftwards(dur time.Duration) (tis []*TimeInt
 // 	Source:        [     |     | ]
 // 	Result:        [ dur | dur | dur ]
 func (ti *TimeInterval) SplitExtend_Rightwards(dur time.Duration) (tis []*TimeInterval) {
+	return ti.splitExtendRightwards(nil, dur)
+}
+
+// Splits interval rightwards using payload policy.
+func (ti *TimeInterval) splitExtendRightwards(p *PayloadPolicy, dur time.Duration) (tis []*TimeInterval) {
 
 	if ti.Len() == 0 {
 		return
@@ -231,10 +238,7 @@ func (ti *TimeInterval) SplitExtend_Rightwards(dur time.Duration) (tis []*TimeIn
 
 		te = ts.Add(dur)
 
-		sub := ti.Clone()
-		sub.Ts = ts
-		sub.Te = te
-		tis = append(tis, sub)
+		tis = append(tis, p.part(ti, ts, te))
 
 		ts = te
 	}
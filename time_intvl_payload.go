@@ -0,0 +1,160 @@
+// Payload propagation through operations.
+//
+// Plain operations keep payload as is: parts cut out of interval
+// share its Data, combined intervals keep first non-nil Data.
+// PayloadPolicy runs same operations with user functions deciding
+// how payload is split and merged, without any package-level state,
+// so that each caller may use its own policy.
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Payload policy model
+//------------------------------------------------------------
+
+// PayloadPolicy decides payload of intervals produced by operations.
+// Nil policy and nil functions mean default behavior.
+type PayloadPolicy struct {
+
+	// Payload of part cut out of whole interval by trim,
+	// exclude or split. If nil, part shares payload of whole.
+	SplitFunc func(data interface{}, whole, part *TimeInterval) interface{}
+
+	// Payload of intervals combined by merge or overlap analysis.
+	// If nil, first non-nil payload is kept.
+	MergeFunc func(a, b interface{}) interface{}
+}
+
+//------------------------------------------------------------
+// Time Interval operations
+//------------------------------------------------------------
+
+// TrimLeft trims interval from left, see TimeInterval.TrimLeft.
+func (p *PayloadPolicy) TrimLeft(ti *TimeInterval, t time.Time) *TimeInterval {
+	return ti.trimLeft(p, t)
+}
+
+// TrimRight trims interval from right, see TimeInterval.TrimRight.
+func (p *PayloadPolicy) TrimRight(ti *TimeInterval, t time.Time) *TimeInterval {
+	return ti.trimRight(p, t)
+}
+
+// Exclude excludes other interval, see TimeInterval.Exclude.
+func (p *PayloadPolicy) Exclude(ti, other *TimeInterval) []*TimeInterval {
+	return ti.exclude(defaultRuntime(), p, other)
+}
+
+// Exclude_Runtime excludes other interval
+// according to runtime boundary semantics.
+func (p *PayloadPolicy) Exclude_Runtime(rt *Runtime, ti, other *TimeInterval) []*TimeInterval {
+	return ti.exclude(rt, p, other)
+}
+
+// Split splits interval, see TimeInterval.Split.
+func (p *PayloadPolicy) Split(ti *TimeInterval, dur time.Duration) []*TimeInterval {
+	return ti.splitBy(p, dur)
+}
+
+// SplitExtend_Leftwards splits interval,
+// see TimeInterval.SplitExtend_Leftwards.
+func (p *PayloadPolicy) SplitExtend_Leftwards(ti *TimeInterval, dur time.Duration) []*TimeInterval {
+	return ti.splitExtendLeftwards(p, dur)
+}
+
+// SplitExtend_Rightwards splits interval,
+// see TimeInterval.SplitExtend_Rightwards.
+func (p *PayloadPolicy) SplitExtend_Rightwards(ti *TimeInterval, dur time.Duration) []*TimeInterval {
+	return ti.splitExtendRightwards(p, dur)
+}
+
+//------------------------------------------------------------
+// Time Intervals operations
+//------------------------------------------------------------
+
+// ExcludeAll excludes interval from each of intervals,
+// see TimeIntervals.Exclude.
+func (p *PayloadPolicy) ExcludeAll(tis TimeIntervals, excl *TimeInterval) TimeIntervals {
+	return tis.exclude(defaultRuntime(), p, excl)
+}
+
+// ExcludeAll_Runtime excludes interval from each of intervals
+// according to runtime boundary semantics.
+func (p *PayloadPolicy) ExcludeAll_Runtime(rt *Runtime, tis TimeIntervals, excl *TimeInterval) TimeIntervals {
+	return tis.exclude(rt, p, excl)
+}
+
+// Merge combines overlapping intervals, see TimeIntervals.Merge.
+func (p *PayloadPolicy) Merge(tis TimeIntervals) TimeIntervals {
+	return tis.merge(defaultRuntime(), p)
+}
+
+// Merge_Runtime combines overlapping intervals
+// according to runtime boundary semantics.
+func (p *PayloadPolicy) Merge_Runtime(rt *Runtime, tis TimeIntervals) TimeIntervals {
+	return tis.merge(rt, p)
+}
+
+// AnalyzeOverlaps analyzes intervals for duplicates and overlaps,
+// see TimeIntervals.AnalyzeOverlaps. Payload of each overlap
+// is merged from parts of both overlapping intervals.
+func (p *PayloadPolicy) AnalyzeOverlaps(tis TimeIntervals) (origs, dups, overs []*TimeInterval) {
+	return tis.analyzeOverlaps(defaultRuntime(), p)
+}
+
+// AnalyzeOverlaps_Runtime analyzes intervals using runtime settings.
+func (p *PayloadPolicy) AnalyzeOverlaps_Runtime(rt *Runtime, tis TimeIntervals) (origs, dups, overs []*TimeInterval) {
+	return tis.analyzeOverlaps(rt, p)
+}
+
+//------------------------------------------------------------
+// Payload functions
+//------------------------------------------------------------
+
+// Returns part [ts, te] of interval with basic fields,
+// Name and Weight copied and payload split.
+func (p *PayloadPolicy) cut(whole *TimeInterval, ts, te time.Time) *TimeInterval {
+
+	part := whole.CloneMin()
+	part.Ts = ts
+	part.Te = te
+	part.Name = whole.Name
+	part.Weight = whole.Weight
+	part.Data = p.splitData(whole, part)
+
+	return part
+}
+
+// Returns part [ts, te] of interval with all fields
+// copied and payload split.
+func (p *PayloadPolicy) part(whole *TimeInterval, ts, te time.Time) *TimeInterval {
+
+	part := whole.Clone()
+	part.Ts = ts
+	part.Te = te
+	part.Data = p.splitData(whole, part)
+
+	return part
+}
+
+// Returns payload of part cut out of whole interval.
+func (p *PayloadPolicy) splitData(whole, part *TimeInterval) interface{} {
+
+	if p == nil || p.SplitFunc == nil {
+		return whole.Data
+	}
+	return p.SplitFunc(whole.Data, whole, part)
+}
+
+// Returns payload of combined intervals.
+func (p *PayloadPolicy) mergeData(a, b interface{}) interface{} {
+
+	switch {
+	case p != nil && p.MergeFunc != nil:
+		return p.MergeFunc(a, b)
+	case a == nil:
+		return b
+	default:
+		return a
+	}
+}
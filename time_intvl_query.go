@@ -0,0 +1,18 @@
+// Point queries of time interval.
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Time Interval point queries
+//------------------------------------------------------------
+
+// Contains checks if t lies within interval according
+// to default runtime boundary semantics.
+//
+//	    [   this   )
+//	--------|--------->
+//	        t
+func (ti *TimeInterval) Contains(t time.Time) bool {
+	return defaultRuntime().Contains(ti, t)
+}
@@ -0,0 +1,263 @@
+// IntervalSet is an immutable set of time in canonical form.
+//
+//	Source:     [___]  [_____]
+//	              [___]      [__]   [_]
+//	Canonical:  [_______________]   [_]
+//
+// Members are sorted by Ts, have positive length and
+// neither overlap nor touch each other. All operations
+// return new sets, so that receiver never has to re-normalize.
+//
+// IntervalSet is coverage-only: it holds time, not intervals.
+// Name, Weight, Data and other fields of source intervals
+// are dropped by NewIntervalSet, members and results of
+// set operations carry none of them. To keep payloads use
+// TimeIntervals operations with PayloadPolicy.
+package intvl
+
+import (
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Interval Set model
+//------------------------------------------------------------
+
+// IntervalSet holds canonical members.
+// Zero value is an empty set.
+type IntervalSet struct {
+	tis TimeIntervals
+}
+
+// NewIntervalSet creates set covering same time as intervals.
+// Only Ts and Te of intervals are used, other fields are dropped.
+// Intervals are not modified.
+func NewIntervalSet(tis ...*TimeInterval) IntervalSet {
+
+	members := make(TimeIntervals, 0, len(tis))
+	for _, ti := range tis {
+		if ti.Len() > 0 {
+			members = append(members, &TimeInterval{Ts: ti.Ts, Te: ti.Te})
+		}
+	}
+
+	sort.Sort(TimeIntervals_ByTs(members))
+	return IntervalSet{tis: mergeSorted(members)}
+}
+
+// Merges sorted members that overlap or touch.
+// Members are modified in place.
+func mergeSorted(tis TimeIntervals) TimeIntervals {
+
+	res := TimeIntervals{}
+	for _, ti := range tis {
+
+		if n := len(res); n != 0 && !ti.Ts.After(res[n-1].Te) {
+			if ti.Te.After(res[n-1].Te) {
+				res[n-1].Te = ti.Te
+			}
+			continue
+		}
+
+		ti.idx = len(res)
+		res = append(res, ti)
+	}
+
+	return res
+}
+
+//------------------------------------------------------------
+// Interval Set qualities
+//------------------------------------------------------------
+
+// Len returns number of members.
+func (s IntervalSet) Len() int {
+	return len(s.tis)
+}
+
+// IsEmpty checks if set covers no time.
+func (s IntervalSet) IsEmpty() bool {
+	return len(s.tis) == 0
+}
+
+// Get returns copy of member at position i.
+func (s IntervalSet) Get(i int) *TimeInterval {
+	return s.tis[i].Clone()
+}
+
+// Intervals returns copies of all members.
+func (s IntervalSet) Intervals() TimeIntervals {
+	return s.tis.Clone()
+}
+
+// IsEqual checks if both sets cover same time.
+func (s IntervalSet) IsEqual(other IntervalSet) bool {
+
+	if len(s.tis) != len(other.tis) {
+		return false
+	}
+
+	for i, ti := range s.tis {
+		if !ti.IsEqual_TsTe(other.tis[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsOverlap checks if set shares any time with interval.
+func (s IntervalSet) IsOverlap(ti *TimeInterval) bool {
+
+	// First member ending after interval start
+	i := sort.Search(len(s.tis), func(i int) bool {
+		return s.tis[i].Te.After(ti.Ts)
+	})
+
+	return i < len(s.tis) && s.tis[i].Ts.Before(ti.Te)
+}
+
+// IsCovers checks if interval lies fully within set.
+func (s IntervalSet) IsCovers(ti *TimeInterval) bool {
+
+	// Member ending at or after interval start
+	i := sort.Search(len(s.tis), func(i int) bool {
+		return !s.tis[i].Te.Before(ti.Ts)
+	})
+
+	return i < len(s.tis) && s.tis[i].IsContains(ti)
+}
+
+// String draws set members.
+func (s IntervalSet) String() string {
+	return s.tis.Clone().String()
+}
+
+//------------------------------------------------------------
+// Interval Set operations
+//------------------------------------------------------------
+
+// Add returns set extended by interval.
+func (s IntervalSet) Add(ti *TimeInterval) IntervalSet {
+	return s.Union(NewIntervalSet(ti))
+}
+
+// Remove returns set without interval.
+func (s IntervalSet) Remove(ti *TimeInterval) IntervalSet {
+	return s.Subtract(NewIntervalSet(ti))
+}
+
+// Union returns set covering time of both sets.
+func (s IntervalSet) Union(other IntervalSet) IntervalSet {
+
+	all := make(TimeIntervals, 0, len(s.tis)+len(other.tis))
+	i, j := 0, 0
+	for i < len(s.tis) || j < len(other.tis) {
+		var ti *TimeInterval
+		if j == len(other.tis) || (i < len(s.tis) && s.tis[i].Ts.Before(other.tis[j].Ts)) {
+			ti = s.tis[i]
+			i++
+		} else {
+			ti = other.tis[j]
+			j++
+		}
+		all = append(all, &TimeInterval{Ts: ti.Ts, Te: ti.Te})
+	}
+
+	return IntervalSet{tis: mergeSorted(all)}
+}
+
+// Intersect returns set covering time common to both sets.
+func (s IntervalSet) Intersect(other IntervalSet) IntervalSet {
+
+	res := TimeIntervals{}
+	i, j := 0, 0
+	for i < len(s.tis) && j < len(other.tis) {
+		a, b := s.tis[i], other.tis[j]
+
+		ts := laterOf(a.Ts, b.Ts)
+		te := earlierOf(a.Te, b.Te)
+		if ts.Before(te) {
+			res = append(res, &TimeInterval{Ts: ts, Te: te, idx: len(res)})
+		}
+
+		// Advance the one that ends first
+		if a.Te.Before(b.Te) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return IntervalSet{tis: res}
+}
+
+// Subtract returns set covering time of this set not covered by other.
+func (s IntervalSet) Subtract(other IntervalSet) IntervalSet {
+
+	res := TimeIntervals{}
+	j := 0
+	for _, ti := range s.tis {
+
+		ts := ti.Ts
+
+		// Skip other members ending before this one
+		for j < len(other.tis) && !other.tis[j].Te.After(ts) {
+			j++
+		}
+
+		// Cut out other members within this one
+		k := j
+		for ; k < len(other.tis) && other.tis[k].Ts.Before(ti.Te); k++ {
+			cut := other.tis[k]
+			if ts.Before(cut.Ts) {
+				res = append(res, &TimeInterval{Ts: ts, Te: cut.Ts, idx: len(res)})
+			}
+			ts = laterOf(ts, cut.Te)
+		}
+
+		if ts.Before(ti.Te) {
+			res = append(res, &TimeInterval{Ts: ts, Te: ti.Te, idx: len(res)})
+		}
+
+		// Last cut may extend into next member
+		if k > j {
+			j = k - 1
+		}
+	}
+
+	return IntervalSet{tis: res}
+}
+
+// SymmetricDifference returns set covering time
+// covered by exactly one of both sets.
+func (s IntervalSet) SymmetricDifference(other IntervalSet) IntervalSet {
+	return s.Subtract(other).Union(other.Subtract(s))
+}
+
+// Complement returns set covering time within bounds
+// not covered by this set.
+func (s IntervalSet) Complement(bounds *TimeInterval) IntervalSet {
+	return NewIntervalSet(bounds).Subtract(s)
+}
+
+//------------------------------------------------------------
+// Helpers
+//------------------------------------------------------------
+
+// Returns earlier of two times.
+func earlierOf(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// Returns later of two times.
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
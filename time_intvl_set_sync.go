@@ -0,0 +1,214 @@
+// SyncIntervalSet is a concurrency-safe mutable interval set.
+//
+// Readers get immutable IntervalSet snapshots, writers replace
+// the snapshot under lock (copy-on-write). Subscribers are
+// notified about each change of covered time, in order of changes,
+// after lock is released, so slow subscribers delay only writers'
+// return, never readers.
+package intvl
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//------------------------------------------------------------
+// Sync Interval Set model
+//------------------------------------------------------------
+
+// SyncIntervalSet is safe for concurrent use.
+// Zero value is an empty set ready to use.
+type SyncIntervalSet struct {
+	mu  sync.RWMutex
+	set IntervalSet
+
+	// Closed when last writer has notified subscribers,
+	// next writer waits for it without holding mu
+	notified chan struct{}
+
+	subsMu  sync.Mutex
+	subs    map[int]func(CoverageChange)
+	subsSeq int
+}
+
+// CoverageChange describes single change of covered time.
+type CoverageChange struct {
+	Added   IntervalSet // time that became covered
+	Removed IntervalSet // time that is no longer covered
+	Set     IntervalSet // snapshot after change
+}
+
+// ReserveError is returned when reservation conflicts
+// with time already covered by set.
+type ReserveError struct {
+	Conflicts TimeIntervals // set members overlapping reservation
+}
+
+func (e *ReserveError) Error() string {
+	return fmt.Sprintf("Reservation conflicts with %v interval(s)", len(e.Conflicts))
+}
+
+// NewSyncIntervalSet creates set covering intervals.
+func NewSyncIntervalSet(tis ...*TimeInterval) *SyncIntervalSet {
+	return &SyncIntervalSet{
+		set:  NewIntervalSet(tis...),
+		subs: map[int]func(CoverageChange){},
+	}
+}
+
+//------------------------------------------------------------
+// Queries
+//------------------------------------------------------------
+
+// Snapshot returns current state of set.
+func (s *SyncIntervalSet) Snapshot() IntervalSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set
+}
+
+// Query returns covered time within interval.
+func (s *SyncIntervalSet) Query(ti *TimeInterval) IntervalSet {
+	return s.Snapshot().Intersect(NewIntervalSet(ti))
+}
+
+// IsOverlap checks if interval shares any time with set.
+func (s *SyncIntervalSet) IsOverlap(ti *TimeInterval) bool {
+	return s.Snapshot().IsOverlap(ti)
+}
+
+//------------------------------------------------------------
+// Modifications
+//------------------------------------------------------------
+
+// Add covers time of intervals.
+func (s *SyncIntervalSet) Add(tis ...*TimeInterval) {
+	s.update(func(set IntervalSet) (IntervalSet, error) {
+		return set.Union(NewIntervalSet(tis...)), nil
+	})
+}
+
+// Remove uncovers time of intervals.
+func (s *SyncIntervalSet) Remove(tis ...*TimeInterval) {
+	s.update(func(set IntervalSet) (IntervalSet, error) {
+		return set.Subtract(NewIntervalSet(tis...)), nil
+	})
+}
+
+// TryReserve atomically covers time of interval
+// if it doesn't overlap time already covered.
+// Otherwise set is unchanged and *ReserveError
+// lists conflicting members.
+func (s *SyncIntervalSet) TryReserve(ti *TimeInterval) error {
+	return s.update(func(set IntervalSet) (IntervalSet, error) {
+		if conflicts := set.overlapping(ti); len(conflicts) != 0 {
+			return set, &ReserveError{Conflicts: conflicts}
+		}
+		return set.Add(ti), nil
+	})
+}
+
+// Replaces set with result of fn and notifies subscribers.
+func (s *SyncIntervalSet) update(fn func(IntervalSet) (IntervalSet, error)) error {
+
+	s.mu.Lock()
+	old := s.set
+	cur, err := fn(old)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.set = cur
+
+	// Take notification turn before next writer can change set
+	prev := s.notified
+	done := make(chan struct{})
+	s.notified = done
+	s.mu.Unlock()
+
+	defer close(done)
+	if prev != nil {
+		<-prev
+	}
+
+	change := CoverageChange{
+		Added:   cur.Subtract(old),
+		Removed: old.Subtract(cur),
+		Set:     cur,
+	}
+
+	if change.Added.IsEmpty() && change.Removed.IsEmpty() {
+		return nil
+	}
+
+	for _, fn := range s.subscribers() {
+		fn(change)
+	}
+
+	return nil
+}
+
+//------------------------------------------------------------
+// Subscriptions
+//------------------------------------------------------------
+
+// Subscribe registers callback called after each change
+// of covered time. Callbacks are called one at a time
+// in order of changes and must not modify the set
+// synchronously. Returned function cancels subscription.
+func (s *SyncIntervalSet) Subscribe(fn func(CoverageChange)) (cancel func()) {
+
+	s.subsMu.Lock()
+	if s.subs == nil {
+		s.subs = map[int]func(CoverageChange){}
+	}
+	id := s.subsSeq
+	s.subsSeq++
+	s.subs[id] = fn
+	s.subsMu.Unlock()
+
+	return func() {
+		s.subsMu.Lock()
+		delete(s.subs, id)
+		s.subsMu.Unlock()
+	}
+}
+
+// Returns subscribers in order of subscription.
+func (s *SyncIntervalSet) subscribers() []func(CoverageChange) {
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	ids := make([]int, 0, len(s.subs))
+	for id := range s.subs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	fns := make([]func(CoverageChange), len(ids))
+	for i, id := range ids {
+		fns[i] = s.subs[id]
+	}
+	return fns
+}
+
+//------------------------------------------------------------
+// Helpers
+//------------------------------------------------------------
+
+// Returns copies of members that overlap interval.
+func (s IntervalSet) overlapping(ti *TimeInterval) (res TimeIntervals) {
+
+	// First member ending after interval start
+	i := sort.Search(len(s.tis), func(i int) bool {
+		return s.tis[i].Te.After(ti.Ts)
+	})
+
+	for ; i < len(s.tis) && s.tis[i].Ts.Before(ti.Te); i++ {
+		res = append(res, s.tis[i].Clone())
+	}
+
+	return
+}
@@ -0,0 +1,214 @@
+package intvl
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+// Tests for Interval Set
+//------------------------------------------------------------
+
+// Tests canonical form.
+func TestIntervalSet(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	ti := &TimeInterval{Ts: h(5), Te: h(7)}
+	s := NewIntervalSet(
+		ti,
+		&TimeInterval{Ts: h(0), Te: h(2)},
+		&TimeInterval{Ts: h(1), Te: h(3)},
+		&TimeInterval{Ts: h(3), Te: h(4)},
+		&TimeInterval{Ts: h(6), Te: h(6)},
+		ti,
+	)
+	fmt.Println(s)
+
+	if s.Len() != 2 || !s.Get(0).IsEqual_TsTe(&TimeInterval{Ts: h(0), Te: h(4)}) {
+		t.Error("IntervalSet must merge overlapping and touching intervals")
+	}
+
+	// Members can't be modified from outside
+	s.Get(0).Te = h(10)
+	s.Intervals()[1].Ts = h(6)
+	if !s.Get(0).Te.Equal(h(4)) || !s.Get(1).Ts.Equal(h(5)) {
+		t.Error("IntervalSet must be immutable")
+	}
+
+	// Operations
+	other := NewIntervalSet(&TimeInterval{Ts: h(2), Te: h(6)})
+
+	if u := s.Union(other); u.Len() != 1 || !u.Get(0).IsEqual_TsTe(&TimeInterval{Ts: h(0), Te: h(7)}) {
+		t.Error("Union failed:", u)
+	}
+	if i := s.Intersect(other); i.Len() != 2 || i.Get(1).Len() != time.Hour {
+		t.Error("Intersect failed:", i)
+	}
+	if d := s.Subtract(other); d.Len() != 2 || !d.Get(1).Ts.Equal(h(6)) {
+		t.Error("Subtract failed:", d)
+	}
+	if c := s.Complement(&TimeInterval{Ts: h(-1), Te: h(8)}); c.Len() != 3 {
+		t.Error("Complement failed:", c)
+	}
+	if !s.IsOverlap(&TimeInterval{Ts: h(3), Te: h(5)}) || s.IsOverlap(&TimeInterval{Ts: h(4), Te: h(5)}) {
+		t.Error("IsOverlap failed")
+	}
+	if !s.IsCovers(&TimeInterval{Ts: h(1), Te: h(4)}) || s.IsCovers(&TimeInterval{Ts: h(3), Te: h(6)}) {
+		t.Error("IsCovers failed")
+	}
+}
+
+// Tests set operations against brute force on a minute grid.
+func TestIntervalSet_Random(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	const size = 120
+	rnd := rand.New(rand.NewSource(1))
+
+	random := func() (IntervalSet, []bool) {
+		grid := make([]bool, size)
+		var tis []*TimeInterval
+		for n := rnd.Intn(6); n > 0; n-- {
+			a, b := rnd.Intn(size), rnd.Intn(size)
+			if a > b {
+				a, b = b, a
+			}
+			for m := a; m < b; m++ {
+				grid[m] = true
+			}
+			tis = append(tis, &TimeInterval{
+				Ts: t0.Add(time.Duration(a) * time.Minute),
+				Te: t0.Add(time.Duration(b) * time.Minute),
+			})
+		}
+		return NewIntervalSet(tis...), grid
+	}
+
+	toGrid := func(s IntervalSet) []bool {
+		grid := make([]bool, size)
+		for i := 0; i < s.Len(); i++ {
+			ti := s.Get(i)
+			if i > 0 && !s.Get(i-1).Te.Before(ti.Ts) {
+				t.Fatal("Members must not touch:", s)
+			}
+			for m := int(ti.Ts.Sub(t0) / time.Minute); m < int(ti.Te.Sub(t0)/time.Minute); m++ {
+				grid[m] = true
+			}
+		}
+		return grid
+	}
+
+	check := func(name string, s IntervalSet, op func(a, b bool) bool, ga, gb []bool) {
+		grid := toGrid(s)
+		for m := range grid {
+			if grid[m] != op(ga[m], gb[m]) {
+				t.Fatal(name, "failed at minute", m)
+			}
+		}
+	}
+
+	bounds := &TimeInterval{Ts: t0, Te: t0.Add(size * time.Minute)}
+	for i := 0; i < 500; i++ {
+		a, ga := random()
+		b, gb := random()
+
+		check("Union", a.Union(b), func(x, y bool) bool { return x || y }, ga, gb)
+		check("Intersect", a.Intersect(b), func(x, y bool) bool { return x && y }, ga, gb)
+		check("Subtract", a.Subtract(b), func(x, y bool) bool { return x && !y }, ga, gb)
+		check("SymmetricDifference", a.SymmetricDifference(b), func(x, y bool) bool { return x != y }, ga, gb)
+		check("Complement", a.Complement(bounds), func(x, y bool) bool { return !x }, ga, gb)
+	}
+}
+
+// Tests concurrent reservations and notifications.
+// Run with -race.
+func TestSyncIntervalSet(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	set := NewSyncIntervalSet(&TimeInterval{Ts: m(0), Te: m(10)})
+
+	// Replica built from notifications only
+	var replica IntervalSet
+	replica = set.Snapshot()
+	events := 0
+	cancel := set.Subscribe(func(ch CoverageChange) {
+		replica = replica.Subtract(ch.Removed).Union(ch.Added)
+		if !replica.IsEqual(ch.Set) {
+			t.Error("Notifications must arrive in order of changes")
+		}
+		events++
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 200; i++ {
+				a := rnd.Intn(1000)
+				ti := &TimeInterval{Ts: m(a), Te: m(a + 1 + rnd.Intn(5))}
+
+				switch rnd.Intn(4) {
+				case 0:
+					set.Add(ti)
+				case 1:
+					set.Remove(ti)
+				default:
+					err := set.TryReserve(ti)
+					if err != nil && len(err.(*ReserveError).Conflicts) == 0 {
+						t.Error("Failed reservation must list conflicts")
+					}
+				}
+				set.Query(ti)
+			}
+		}(g)
+	}
+	wg.Wait()
+	cancel()
+
+	if !replica.IsEqual(set.Snapshot()) || events == 0 {
+		t.Error("Notifications must describe all changes")
+	}
+
+	// Reservation conflicts
+	set = NewSyncIntervalSet(&TimeInterval{Ts: m(0), Te: m(10)}, &TimeInterval{Ts: m(20), Te: m(30)})
+	err := set.TryReserve(&TimeInterval{Ts: m(5), Te: m(25)})
+	if rerr, ok := err.(*ReserveError); !ok || len(rerr.Conflicts) != 2 {
+		t.Error("TryReserve must fail with conflicting intervals:", err)
+	}
+	if err := set.TryReserve(&TimeInterval{Ts: m(10), Te: m(20)}); err != nil || set.Snapshot().Len() != 1 {
+		t.Error("TryReserve must accept touching interval:", err)
+	}
+
+	// Slow subscriber of zero value set doesn't block readers
+	var zero SyncIntervalSet
+	release := make(chan struct{})
+	cancel = zero.Subscribe(func(ch CoverageChange) { <-release })
+	defer cancel()
+
+	go zero.Add(&TimeInterval{Ts: m(0), Te: m(10)})
+	go zero.Add(&TimeInterval{Ts: m(20), Te: m(30)})
+
+	read := make(chan bool)
+	go func() {
+		for zero.Snapshot().Len() != 2 {
+			time.Sleep(time.Millisecond)
+		}
+		read <- true
+	}()
+
+	select {
+	case <-read:
+	case <-time.After(2 * time.Second):
+		t.Error("Readers must not wait for subscribers")
+	}
+	close(release)
+}
@@ -1,7 +1,10 @@
 package intvl
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -15,6 +18,86 @@ func TestRuntime(t *testing.T) {
 
 	// Set debug output format
 	Runtime_TimeLayout_Debug("Jan _2 15:04:05 MST")
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	ti := &TimeInterval{Ts: t0, Te: t0.Add(time.Hour)}
+
+	// Per-instance runtime doesn't affect default one
+	rt := NewRuntime()
+	rt.TimeLayoutDebug = time.RFC3339
+	if str := ti.String_Runtime(rt); !strings.Contains(str, "2015-03-15T12:00:00Z") {
+		t.Error("String_Runtime must use runtime layout:", str)
+	}
+	if str := ti.String(); strings.Contains(str, "2015-03-15T12:00:00Z") {
+		t.Error("String must use default runtime layout:", str)
+	}
+
+	// Default runtime is safe for concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Runtime_TimeLayout_Debug("Jan _2 15:04:05 MST")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = ti.String()
+		}()
+	}
+	wg.Wait()
+
+	// Runtime parser reads times in debug layout
+	tiBack, err := NewRuntime().Parser().Parse(
+		"2015 Mar 15 12:00:00 UTC --- 2015 Mar 15 13:00:00 UTC")
+	if err != nil || !tiBack.IsEqual_TsTe(ti) {
+		t.Error("Runtime parser must read debug layout:", tiBack, err)
+	}
+}
+
+// Tests runtime tolerance in analysis.
+func TestRuntime_Tolerance(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	bounds := &TimeInterval{Ts: t0, Te: t0.Add(4 * time.Hour)}
+	tis := NewTimeIntervals(
+		&TimeInterval{Ts: t0, Te: t0.Add(time.Hour)},
+		&TimeInterval{Ts: t0.Add(time.Hour + time.Second), Te: t0.Add(4 * time.Hour)},
+	)
+
+	if gaps := tis.AnalyzeRelativeTo(bounds).Gaps(); len(gaps) != 1 {
+		t.Error("Analysis must find gap of 1s")
+	}
+
+	rt := NewRuntime()
+	rt.Tolerance = time.Minute
+	if gaps := tis.AnalyzeRelativeTo_Runtime(rt, bounds).Gaps(); len(gaps) != 0 {
+		t.Error("Analysis must ignore gaps within tolerance")
+	}
+
+	// Boundary semantics
+	a := &TimeInterval{Ts: t0, Te: t0.Add(time.Hour)}
+	b := &TimeInterval{Ts: t0.Add(time.Hour), Te: t0.Add(2 * time.Hour)}
+	if rt.IsOverlap(a, b) {
+		t.Error("Touching intervals must not overlap in [) semantics")
+	}
+	if _, _, overs := NewTimeIntervals(a, b).AnalyzeOverlaps(); len(overs) != 0 {
+		t.Error("Touching intervals must not be reported as overlap in [) semantics")
+	}
+
+	rt = NewRuntime()
+	rt.Boundary = BOUNDS_CLOSED
+	if !rt.IsOverlap(a, b) {
+		t.Error("Touching intervals must overlap in [] semantics")
+	}
+	_, _, overs := NewTimeIntervals(a, b).AnalyzeOverlaps_Runtime(rt)
+	if len(overs) != 1 || overs[0].Len() != 0 || !overs[0].Ts.Equal(b.Ts) {
+		t.Error("Touching intervals must overlap at a point in [] semantics:", overs)
+	}
+	if rems := a.Exclude_Runtime(rt, b); len(rems) != 1 || !rems[0].IsEqual_TsTe(a) {
+		t.Error("Exclude of touching interval must keep interval:", rems)
+	}
 }
 
 // Tests base operations.
@@ -401,3 +484,124 @@ func TestOp(t *testing.T) {
 	tis = src.SplitExtend_Rightwards(180 * time.Minute)
 	fmt.Println(NewTimeIntervals(tis...))
 }
+
+// Tests validated construction.
+func TestValidate(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	ti, err := NewTimeInterval(t0, t0.Add(time.Hour), WithDt(time.Minute), WithName("One"))
+	if err != nil || ti.Name != "One" || ti.Dt != time.Minute {
+		t.Error("NewTimeInterval failed:", err)
+	}
+
+	// All violations reported
+	_, err = NewTimeInterval(t0, time.Time{},
+		WithDt(-time.Minute), WithTimes(t0.Add(-time.Hour)))
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 4 {
+		t.Fatal("NewTimeInterval must report all violations:", err)
+	}
+	fmt.Println(err)
+
+	// Positive dt required unless zero allowed
+	ti = &TimeInterval{Ts: t0, Te: t0.Add(time.Hour)}
+	if ti.Validate() == nil || ti.Validate(VALIDATE_ALLOW_ZERO_DT) != nil {
+		t.Error("Validate must require positive dt unless zero allowed")
+	}
+	if _, err := NewTimeInterval(t0, t0.Add(time.Hour)); err == nil {
+		t.Error("NewTimeInterval must reject zero dt")
+	}
+
+	// Setters
+	ti = &TimeInterval{}
+	if ti.SetEnd(t0) != nil || ti.SetStart(t0.Add(time.Hour)) == nil || !ti.Ts.IsZero() {
+		t.Error("SetStart must fail and leave interval unchanged")
+	}
+
+	// Intervals
+	tis := TimeIntervals{
+		&TimeInterval{Ts: t0, Te: t0.Add(2 * time.Hour)},
+		&TimeInterval{Ts: t0.Add(3 * time.Hour), Te: t0.Add(4 * time.Hour)},
+		&TimeInterval{Ts: t0.Add(time.Hour), Te: t0.Add(90 * time.Minute)},
+		&TimeInterval{Ts: t0.Add(4 * time.Hour), Te: t0.Add(5 * time.Hour)},
+	}
+
+	if err := tis.Validate(); err == nil {
+		t.Error("Validate must report unsorted intervals")
+	}
+
+	tis = tis.SortByTs()
+	if err := tis.Validate(VALIDATE_ALLOW_ZERO_DT); err != nil {
+		t.Error("Validate must pass sorted intervals:", err)
+	}
+	if err := tis.Validate(); !errors.As(err, &errs) || len(errs) != 4 || errs[0].Rule != INVALID_DT {
+		t.Error("Validate must report zero dt of each interval:", err)
+	}
+
+	err = tis.Validate(VALIDATE_NO_OVERLAP, VALIDATE_ALLOW_ZERO_DT)
+	fmt.Println(err)
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Rule != INVALID_OVERLAP || errs[0].Idx != 1 {
+		t.Error("Validate must report only overlapping interval:", err)
+	}
+}
+
+// Tests payload propagation.
+func TestPayload(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	// Payload is amount spread evenly over interval
+	p := &PayloadPolicy{
+		SplitFunc: func(data interface{}, whole, part *TimeInterval) interface{} {
+			return data.(float64) * float64(part.Len()) / float64(whole.Len())
+		},
+		MergeFunc: func(a, b interface{}) interface{} {
+			return a.(float64) + b.(float64)
+		},
+	}
+
+	ti := &TimeInterval{Ts: h(0), Te: h(4), Name: "a", Data: 100.0}
+
+	// Plain operations share payload
+	if part := ti.TrimLeft(h(1)); part.Data != 100.0 || part.Name != "a" {
+		t.Error("TrimLeft must keep Name and payload:", part)
+	}
+
+	if part := p.TrimRight(ti, h(1)); part.Data != 25.0 {
+		t.Error("TrimRight must split payload:", part.Data)
+	}
+
+	parts := p.Exclude(ti, &TimeInterval{Ts: h(1), Te: h(2)})
+	if len(parts) != 2 || parts[0].Data != 25.0 || parts[1].Data != 50.0 || parts[1].Name != "a" {
+		t.Error("Exclude must split payload")
+	}
+
+	parts = p.Split(ti, time.Hour)
+	if len(parts) != 4 || parts[3].Data != 25.0 {
+		t.Error("Split must split payload")
+	}
+
+	// Merge combines payloads
+	tis := NewTimeIntervals(ti, &TimeInterval{Ts: h(3), Te: h(6), Name: "b", Data: 30.0})
+	if mg := p.Merge(tis); len(mg) != 1 || mg[0].Data != 130.0 || mg[0].Name != "a,b" {
+		t.Error("Merge must combine payloads:", mg)
+	}
+
+	tis = NewTimeIntervals(ti, &TimeInterval{Ts: h(3), Te: h(6), Name: "b", Data: 30.0})
+	if mg := tis.Merge(); len(mg) != 1 || mg[0].Data != 100.0 {
+		t.Error("Merge must keep first payload by default:", mg)
+	}
+
+	// Overlap fully inside original doesn't modify source
+	inner := &TimeInterval{Ts: h(1), Te: h(2), Name: "b", Data: 10.0}
+	_, _, overs := p.AnalyzeOverlaps(NewTimeIntervals(ti, inner))
+	if len(overs) != 1 || overs[0].Data != 35.0 || overs[0].Name != "a,b" {
+		t.Error("AnalyzeOverlaps must merge payloads:", overs)
+	}
+	if inner.Name != "b" || inner.Data != 10.0 {
+		t.Error("AnalyzeOverlaps must not modify source intervals")
+	}
+}
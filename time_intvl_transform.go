@@ -0,0 +1,113 @@
+// Transformations of time interval.
+// Each transformation returns new interval, receiver is not modified.
+// Discrete Times follow the interval: they are moved with it
+// and those falling outside of new bounds are dropped.
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Time Interval transformations
+//------------------------------------------------------------
+
+// Shift moves interval by d.
+//
+//	Source:     [ this ]
+//	Result:          [ ti   ]
+//	            |-d->|
+func (this *TimeInterval) Shift(d time.Duration) (ti *TimeInterval) {
+
+	ti = this.Clone()
+	ti.Ts = this.Ts.Add(d)
+	ti.Te = this.Te.Add(d)
+
+	for i, t := range ti.Times {
+		ti.Times[i] = t.Add(d)
+	}
+
+	return
+}
+
+// Expand pads interval by left and right durations.
+// Negative durations shrink interval.
+// Returns nil if nothing remains.
+//
+//	Source:         [ this ]
+//	Result:    [        ti      ]
+//	           |left|      |right|
+func (this *TimeInterval) Expand(left, right time.Duration) (ti *TimeInterval) {
+	return this.bound(this.Ts.Add(-left), this.Te.Add(right))
+}
+
+// Shrink cuts left and right durations off interval.
+// Returns nil if nothing remains.
+//
+//	Source:    [      this      ]
+//	Result:         [  ti  ]
+//	           |left|      |right|
+func (this *TimeInterval) Shrink(left, right time.Duration) (ti *TimeInterval) {
+	return this.Expand(-left, -right)
+}
+
+// Clamp limits interval to bounds.
+// Returns nil if interval lies outside of bounds.
+//
+//	Source:       [    this    ]
+//	Bounds:   [      bounds ]
+//	Result:       [   ti    ]
+func (this *TimeInterval) Clamp(bounds *TimeInterval) (ti *TimeInterval) {
+	return this.bound(laterOf(this.Ts, bounds.Ts), earlierOf(this.Te, bounds.Te))
+}
+
+// Scale stretches interval by factor relative to anchor,
+// anchor stays in place. Factor must be positive.
+// Returns nil if factor is not positive.
+//
+//	Source:        [ this ]
+//	Result:           [      ti      ]    factor = 2
+//	          anchor
+//	          |----|------>
+func (this *TimeInterval) Scale(factor float64, anchor time.Time) (ti *TimeInterval) {
+
+	if factor <= 0 {
+		return nil
+	}
+
+	scale := func(t time.Time) time.Time {
+		return anchor.Add(time.Duration(float64(t.Sub(anchor)) * factor))
+	}
+
+	ti = this.Clone()
+	ti.Ts = scale(this.Ts)
+	ti.Te = scale(this.Te)
+
+	for i, t := range ti.Times {
+		ti.Times[i] = scale(t)
+	}
+
+	return
+}
+
+// Returns copy of interval with new bounds
+// or nil if new bounds are empty.
+func (this *TimeInterval) bound(ts, te time.Time) (ti *TimeInterval) {
+
+	if !ts.Before(te) {
+		return nil
+	}
+
+	ti = this.Clone()
+	ti.Ts = ts
+	ti.Te = te
+
+	// Keep moments within new bounds
+	times := ti.Times[:0]
+	for _, t := range ti.Times {
+		if !t.Before(ts) && !t.After(te) {
+			times = append(times, t)
+		}
+	}
+	ti.Times = times
+
+	return
+}
@@ -0,0 +1,118 @@
+// Validation of time interval invariants.
+package intvl
+
+import (
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------
+// Validation errors
+//------------------------------------------------------------
+
+// ValidationError describes single invariant violation.
+type ValidationError struct {
+	Idx  int    // position in TimeIntervals, 0 for single interval
+	Rule string // one of INVALID_*
+	Msg  string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid TimeInterval at position %v: %v", e.Idx, e.Msg)
+}
+
+// ValidationErrors collects all invariant violations.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs ValidationErrors) Unwrap() []error {
+	res := make([]error, len(errs))
+	for i, e := range errs {
+		res[i] = e
+	}
+	return res
+}
+
+// Returns nil error if there are no violations.
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+//------------------------------------------------------------
+// Time Interval validation
+//------------------------------------------------------------
+
+// Validate checks interval invariants:
+// Ts and Te are set, Te is not before Ts,
+// DtMode is known, Dt is positive in homogenous mode
+// and not negative in discrete mode,
+// and discrete Times lie within [Ts, Te].
+// Rule VALIDATE_ALLOW_ZERO_DT accepts zero Dt
+// in homogenous mode, for intervals without granularity.
+// Returns ValidationErrors listing all violations.
+func (ti *TimeInterval) Validate(rules ...string) error {
+	return ti.validate(0, hasRule(rules, VALIDATE_ALLOW_ZERO_DT)).err()
+}
+
+// Collects violations of interval at position idx.
+func (ti *TimeInterval) validate(idx int, allowZeroDt bool) (errs ValidationErrors) {
+
+	fail := func(rule, format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{
+			Idx:  idx,
+			Rule: rule,
+			Msg:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if ti.Ts.IsZero() {
+		fail(INVALID_ZERO_TIME, "Ts is zero")
+	}
+	if ti.Te.IsZero() {
+		fail(INVALID_ZERO_TIME, "Te is zero")
+	}
+	if ti.Te.Before(ti.Ts) {
+		fail(INVALID_ORDER, "end before start")
+	}
+
+	switch ti.DtMode {
+	case DTMODE_HOMOGENOUS:
+		if ti.Dt < 0 || (!allowZeroDt && ti.Dt == 0) {
+			fail(INVALID_DT, "dt = %v must be positive", ti.Dt)
+		}
+	case DTMODE_DISCRETE:
+		if ti.Dt < 0 {
+			fail(INVALID_DT, "dt = %v is negative", ti.Dt)
+		}
+	default:
+		fail(INVALID_DTMODE, "unknown dtMode = %v", ti.DtMode)
+	}
+
+	for i, t := range ti.Times {
+		if t.Before(ti.Ts) || t.After(ti.Te) {
+			fail(INVALID_TIMES, "times[%v] = %v outside of interval", i, t)
+		}
+	}
+
+	return
+}
+
+// Checks if rule is among rules.
+func hasRule(rules []string, rule string) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -28,14 +28,42 @@ func NewTimeIntervals(tis ...*TimeInterval) TimeIntervals {
 	for i, ti := range intvls {
 		ti.idx = i
 	}
+	intvls.buildIndex()
 
 	return intvls
 }
 
-// Clones time intervals.
+// NewTimeIntervals_Copy creates sorted array of deep copies
+// of intervals normalized to UTC. Supplied intervals are not modified.
+func NewTimeIntervals_Copy(tis ...*TimeInterval) TimeIntervals {
+	return NewTimeIntervals(TimeIntervals(tis).Clone()...)
+}
+
+// NewTimeIntervals_KeepLocation creates sorted array of deep copies
+// of intervals keeping original location of each time.
+// Supplied intervals are not modified.
+func NewTimeIntervals_KeepLocation(tis ...*TimeInterval) TimeIntervals {
+
+	// Create and sort
+	intvls := TimeIntervals(tis).Clone()
+	sort.Sort(TimeIntervals_ByTs(intvls))
+
+	// Set inner indexes
+	for i, ti := range intvls {
+		ti.idx = i
+	}
+	intvls.buildIndex()
+
+	return intvls
+}
+
+// Clones time intervals producing deep copy of each interval.
 func (tis TimeIntervals) Clone() TimeIntervals {
 	clone := make([]*TimeInterval, len(tis))
-	copy(clone, tis)
+	for i, ti := range tis {
+		clone[i] = ti.Clone()
+		clone[i].idx = ti.idx
+	}
 	return clone
 }
 
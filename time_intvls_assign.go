@@ -0,0 +1,130 @@
+// Batch assignment of sorted points to intervals.
+//
+//	  [ a ]  [   b   ]  [ c ]
+//	----|--------|---|---|----->
+//	Covering:    a   b   -   c
+//	As-of:       a   b   b   c
+package intvl
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Assignment
+//------------------------------------------------------------
+
+// Assign returns position of interval for each of times
+// according to mode, one of ASSIGN_*, or -1 if there is none.
+// Intervals must be sorted by Ts and times must be sorted.
+// Results are same as of At, Prev and Nearest for each time,
+// found in a single sweep. Panics if mode is not one of ASSIGN_*.
+func (tis TimeIntervals) Assign(times []time.Time, mode string) []int {
+	return tis.Assign_Runtime(defaultRuntime(), times, mode)
+}
+
+// Assign_Runtime assigns times to intervals
+// according to runtime boundary semantics.
+func (tis TimeIntervals) Assign_Runtime(rt *Runtime, times []time.Time, mode string) (res []int) {
+
+	switch mode {
+	case ASSIGN_COVERING, ASSIGN_ASOF, ASSIGN_NEAREST:
+	default:
+		panic("Invalid Assign: unknown mode " + mode)
+	}
+
+	res = make([]int, len(times))
+
+	// Started intervals not known to have ended
+	active := &activeHeap{}
+
+	// Intervals in order of ending, ties by position
+	byTe := make([]int, len(tis))
+	for i := range byTe {
+		byTe[i] = i
+	}
+	sort.SliceStable(byTe, func(i, j int) bool {
+		return tis[byTe[i]].Te.Before(tis[byTe[j]].Te)
+	})
+
+	started, ended := 0, 0
+	for k, t := range times {
+
+		for started < len(tis) && !tis[started].Ts.After(t) {
+			heap.Push(active, started)
+			started++
+		}
+		for ended < len(byTe) && rt.isEndedBy(tis[byTe[ended]], t) {
+			ended++
+		}
+
+		// Latest starting interval containing t: ended ones
+		// never contain later times and are dropped for good,
+		// started one that has not ended contains t
+		for active.Len() != 0 && rt.isEndedBy(tis[active.peek()], t) {
+			heap.Pop(active)
+		}
+		at := -1
+		if active.Len() != 0 {
+			at = active.peek()
+		}
+
+		prev := -1
+		if ended > 0 {
+			prev = byTe[ended-1]
+		}
+
+		next := -1
+		if started < len(tis) {
+			next = started
+		}
+
+		switch {
+		case at != -1 || mode == ASSIGN_COVERING:
+			res[k] = at
+		case mode == ASSIGN_ASOF:
+			res[k] = prev
+		case mode == ASSIGN_NEAREST:
+			res[k] = tis.closer(t, prev, next)
+		}
+	}
+
+	return
+}
+
+//------------------------------------------------------------
+// Active heap
+//------------------------------------------------------------
+
+// Max-heap of interval positions, latest starting on top.
+type activeHeap []int
+
+func (h activeHeap) Len() int {
+	return len(h)
+}
+
+func (h activeHeap) Less(i, j int) bool {
+	return h[i] > h[j]
+}
+
+func (h activeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *activeHeap) Push(x interface{}) {
+	*h = append(*h, x.(int))
+}
+
+func (h *activeHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Returns position of latest starting interval.
+func (h activeHeap) peek() int {
+	return h[0]
+}
@@ -1,6 +1,8 @@
 // TimeIntervals comparison.
 package intvl
 
+import "time"
+
 //------------------------------------------------------------
 // Time Intervals comparison.
 //------------------------------------------------------------
@@ -21,3 +23,16 @@ func (tis TimeIntervals) IsEqual(other TimeIntervals) bool {
 
 	return true
 }
+
+// IsEqual_Coverage compares time covered by intervals,
+// regardless of order and fragmentation:
+// [a, b) and [b, c) are equal to [a, c).
+func (tis TimeIntervals) IsEqual_Coverage(other TimeIntervals) bool {
+	return NewIntervalSet(tis...).IsEqual(NewIntervalSet(other...))
+}
+
+// IsEqual_Tolerance compares [Ts:Te) of intervals one by one
+// within tolerance, such as clock skew.
+func (tis TimeIntervals) IsEqual_Tolerance(other TimeIntervals, tolerance time.Duration) bool {
+	return (&Comparator{Tolerance: tolerance}).IsEqual_TimeIntervals(tis, other)
+}
@@ -0,0 +1,352 @@
+// TimeIntervals import and export in CSV format.
+//
+//	name,start,end,dt
+//	Backup,2015-03-15T02:00:00Z,2015-03-15T04:00:00Z,15m0s
+//
+// Columns are mapped by header names, see CSVFormat.
+package intvl
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+// CSV format model
+//------------------------------------------------------------
+
+// CSVFormat describes mapping of interval fields to CSV columns.
+// Column names are matched against header row case-insensitively.
+// Empty column name means the field is not present in CSV.
+// Reading requires Start and at least one of End or Duration.
+type CSVFormat struct {
+	Name     string
+	Start    string
+	End      string
+	Duration string
+	Dt       string
+
+	Layout   string         // time layout of Start and End, RFC3339 if empty
+	Location *time.Location // location of times without zone
+	Comma    rune           // field delimiter
+}
+
+// NewCSVFormat creates format with columns "name,start,end,dt"
+// and RFC3339 times in UTC.
+func NewCSVFormat() *CSVFormat {
+	return &CSVFormat{
+		Name:     "name",
+		Start:    "start",
+		End:      "end",
+		Dt:       "dt",
+		Layout:   time.RFC3339,
+		Location: time.UTC,
+		Comma:    ',',
+	}
+}
+
+// Returns format layout or RFC3339 if not set.
+func (format *CSVFormat) layout() string {
+	if format.Layout == "" {
+		return time.RFC3339
+	}
+	return format.Layout
+}
+
+//------------------------------------------------------------
+// CSV errors
+//------------------------------------------------------------
+
+// CSVError describes failure to read one CSV row.
+type CSVError struct {
+	Line   int    // line number in input, starting from 1
+	Column string // column name, empty if row as a whole is wrong
+	Value  string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("CSV line %v: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("CSV line %v, column %v = %q: %v",
+		e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+// CSVErrors collects errors of all rows that failed to read.
+type CSVErrors []*CSVError
+
+func (errs CSVErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%v CSV rows failed: %v",
+		len(errs), strings.Join(msgs, "; "))
+}
+
+func (errs CSVErrors) Unwrap() []error {
+	res := make([]error, len(errs))
+	for i, e := range errs {
+		res[i] = e
+	}
+	return res
+}
+
+//------------------------------------------------------------
+// Import
+//------------------------------------------------------------
+
+// ReadCSV reads time intervals from CSV with header row.
+// Rows that fail to parse don't stop reading: all valid rows
+// are returned together with CSVErrors describing each failed row.
+// Other errors, such as missing columns in header, are returned as is.
+func ReadCSV(r io.Reader, format *CSVFormat) (tis TimeIntervals, err error) {
+
+	if format == nil {
+		format = NewCSVFormat()
+	}
+
+	loc := format.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if format.Comma != 0 {
+		reader.Comma = format.Comma
+	}
+
+	// Header
+	var header []string
+	if header, err = reader.Read(); err != nil {
+		if err == io.EOF {
+			err = errors.New("CSV: missing header row")
+		}
+		return
+	}
+
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	column := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		if i, ok := cols[strings.ToLower(name)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	iName := column(format.Name)
+	iStart := column(format.Start)
+	iEnd := column(format.End)
+	iDuration := column(format.Duration)
+	iDt := column(format.Dt)
+
+	if iStart == -1 {
+		err = fmt.Errorf("CSV: missing start column %q", format.Start)
+		return
+	}
+	if iEnd == -1 && iDuration == -1 {
+		err = fmt.Errorf("CSV: missing end column %q or duration column %q",
+			format.End, format.Duration)
+		return
+	}
+
+	// Rows
+	var errs CSVErrors
+	var tiis []*TimeInterval
+
+	for {
+		record, e := reader.Read()
+		if e == io.EOF {
+			break
+		}
+
+		if e != nil {
+			perr, ok := e.(*csv.ParseError)
+			if !ok {
+				err = e
+				return
+			}
+			errs = append(errs, &CSVError{Line: perr.Line, Err: perr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		// Skip blank lines within spreadsheet exports
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		ti, rowErr := csvRow(record, line, format, loc,
+			iName, iStart, iEnd, iDuration, iDt)
+		if rowErr != nil {
+			errs = append(errs, rowErr)
+			continue
+		}
+
+		tiis = append(tiis, ti)
+	}
+
+	tis = NewTimeIntervals(tiis...)
+	if len(errs) != 0 {
+		err = errs
+	}
+	return
+}
+
+// Converts CSV record into time interval.
+func csvRow(record []string, line int, format *CSVFormat, loc *time.Location,
+	iName, iStart, iEnd, iDuration, iDt int) (ti *TimeInterval, err *CSVError) {
+
+	field := func(i int) string {
+		if i == -1 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	fail := func(column, value string, e error) *CSVError {
+		return &CSVError{Line: line, Column: column, Value: value, Err: e}
+	}
+
+	ti = &TimeInterval{Name: field(iName)}
+
+	// Start
+	str := field(iStart)
+	if str == "" {
+		return nil, fail(format.Start, str, errors.New("Empty start time"))
+	}
+
+	var e error
+	if ti.Ts, e = time.ParseInLocation(format.layout(), str, loc); e != nil {
+		return nil, fail(format.Start, str, e)
+	}
+
+	// End or duration
+	var dur time.Duration
+	if str = field(iDuration); str != "" {
+		if dur, e = parseDuration(str); e != nil {
+			return nil, fail(format.Duration, str, e)
+		}
+	}
+
+	if str = field(iEnd); str != "" {
+		if ti.Te, e = time.ParseInLocation(format.layout(), str, loc); e != nil {
+			return nil, fail(format.End, str, e)
+		}
+		if dur != 0 && ti.Len() != dur {
+			return nil, fail(format.Duration, field(iDuration),
+				errors.New("Duration doesn't match start and end"))
+		}
+	} else if dur != 0 {
+		ti.Te = ti.Ts.Add(dur)
+	} else {
+		return nil, fail("", "", errors.New("Missing both end time and duration"))
+	}
+
+	if !ti.Ts.Before(ti.Te) {
+		return nil, fail("", "", errors.New("Invalid TimeInterval: Ts must be before Te"))
+	}
+
+	// Granularity
+	if str = field(iDt); str != "" {
+		if ti.Dt, e = parseDuration(str); e != nil {
+			return nil, fail(format.Dt, str, e)
+		}
+	}
+
+	return ti, nil
+}
+
+//------------------------------------------------------------
+// Export
+//------------------------------------------------------------
+
+// WriteCSV writes intervals as CSV with header row.
+// Only columns with non-empty names in format are written.
+func (tis TimeIntervals) WriteCSV(w io.Writer, format *CSVFormat) error {
+
+	if format == nil {
+		format = NewCSVFormat()
+	}
+
+	loc := format.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	writer := csv.NewWriter(w)
+	if format.Comma != 0 {
+		writer.Comma = format.Comma
+	}
+
+	// Columns in fixed order
+	type column struct {
+		name  string
+		value func(ti *TimeInterval) string
+	}
+
+	all := []column{
+		{format.Name, func(ti *TimeInterval) string {
+			return ti.Name
+		}},
+		{format.Start, func(ti *TimeInterval) string {
+			return ti.Ts.In(loc).Format(format.layout())
+		}},
+		{format.End, func(ti *TimeInterval) string {
+			return ti.Te.In(loc).Format(format.layout())
+		}},
+		{format.Duration, func(ti *TimeInterval) string {
+			return ti.Len().String()
+		}},
+		{format.Dt, func(ti *TimeInterval) string {
+			if ti.Dt == 0 {
+				return ""
+			}
+			return ti.Dt.String()
+		}},
+	}
+
+	var cols []column
+	var header []string
+	for _, col := range all {
+		if col.name != "" {
+			cols = append(cols, col)
+			header = append(header, col.name)
+		}
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(cols))
+	for _, ti := range tis {
+		for i, col := range cols {
+			record[i] = col.value(ti)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
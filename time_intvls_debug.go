@@ -43,6 +43,11 @@ func (tis TimeIntervals) Dump() string {
 }
 
 func (tis TimeIntervals) String() string {
+	return tis.String_Runtime(defaultRuntime())
+}
+
+// String_Runtime draws intervals using runtime settings.
+func (tis TimeIntervals) String_Runtime(rt *Runtime) string {
 
 	// Check if some pointers point at same interval
 	for i, ti := range tis {
@@ -235,7 +240,7 @@ func (tis TimeIntervals) String() string {
 		buf.WriteString(string(line))
 		buf.WriteString(" : ")
 		if ti != nil {
-			buf.WriteString(dumpToStringLine("", ti.Dump()))
+			buf.WriteString(dumpToStringLine("", ti.Dump_Runtime(rt)))
 		} else {
 			buf.WriteString("TimeInterval <NOT FOUND>")
 		}
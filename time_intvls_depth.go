@@ -0,0 +1,107 @@
+// Depth profile shows how many intervals are active over time.
+//
+//	Source:  [_____]
+//	            [_____]   [__]
+//	Depth:   1  2  1  0   1
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Depth profile model
+//------------------------------------------------------------
+
+// DepthStep is a period of constant depth.
+type DepthStep struct {
+	Ts    time.Time
+	Te    time.Time
+	Depth int
+}
+
+// Len returns length of step.
+func (s DepthStep) Len() time.Duration {
+	return s.Te.Sub(s.Ts)
+}
+
+// DepthProfile is a step function of depth, steps
+// are contiguous and sorted, adjacent steps differ in depth.
+// Steps cover time from earliest start to latest end,
+// including steps of zero depth between intervals.
+type DepthProfile []DepthStep
+
+//------------------------------------------------------------
+// Depth profile creation
+//------------------------------------------------------------
+
+// Depth sweeps through start and end points of intervals
+// and returns depth profile. Points at equal times are
+// applied together, so touching intervals don't overlap.
+func (tis TimeIntervals) Depth() (p DepthProfile) {
+
+	load := tis.sweep(func(ti *TimeInterval) float64 { return 1 })
+
+	p = make(DepthProfile, len(load))
+	for i, s := range load {
+		p[i] = DepthStep{Ts: s.Ts, Te: s.Te, Depth: int(s.Load)}
+	}
+	return
+}
+
+//------------------------------------------------------------
+// Depth profile queries
+//------------------------------------------------------------
+
+// Max returns maximum depth.
+func (p DepthProfile) Max() (max int) {
+
+	for _, s := range p {
+		if s.Depth > max {
+			max = s.Depth
+		}
+	}
+	return
+}
+
+// At returns depth at time t.
+// Step includes its start and excludes its end.
+func (p DepthProfile) At(t time.Time) int {
+
+	for _, s := range p {
+		if !t.Before(s.Ts) && t.Before(s.Te) {
+			return s.Depth
+		}
+	}
+	return 0
+}
+
+// TimeAtDepth returns total time spent at each depth.
+func (p DepthProfile) TimeAtDepth() map[int]time.Duration {
+
+	res := map[int]time.Duration{}
+	for _, s := range p {
+		res[s.Depth] += s.Len()
+	}
+	return res
+}
+
+// AtLeast returns regions where depth >= k,
+// adjacent steps are merged into one region.
+func (p DepthProfile) AtLeast(k int) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, s := range p {
+
+		if s.Depth < k {
+			continue
+		}
+
+		if n := len(res); n != 0 && res[n-1].Te.Equal(s.Ts) {
+			res[n-1].Te = s.Te
+			continue
+		}
+
+		res = append(res, &TimeInterval{Ts: s.Ts, Te: s.Te, idx: len(res)})
+	}
+
+	return
+}
@@ -0,0 +1,163 @@
+// Difference between two versions of a schedule.
+//
+//	Old:       [__a__]    [__b__]
+//	New:          [__a__]   [_b_]     [c]
+//	Changes:   a moved, b shortened, c added
+package intvl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------
+// Diff model
+//------------------------------------------------------------
+
+// ScheduleDiff describes what changed between versions.
+type ScheduleDiff struct {
+	Added   IntervalSet // time covered only by new version
+	Removed IntervalSet // time covered only by old version
+	Changes []IntervalChange
+}
+
+// IntervalChange describes change of single interval.
+// Old is nil for added intervals, New is nil for removed ones.
+type IntervalChange struct {
+	Old   *TimeInterval
+	New   *TimeInterval
+	Kinds []string // one or more of DIFF_*
+}
+
+//------------------------------------------------------------
+// Diff
+//------------------------------------------------------------
+
+// Diff compares versions of schedule matching intervals by Name.
+func Diff(before, after TimeIntervals) *ScheduleDiff {
+	return Diff_Key(before, after, func(ti *TimeInterval) string { return ti.Name })
+}
+
+// Diff_Key compares versions of schedule matching intervals
+// by key. Intervals with repeated keys are matched in order.
+// Unchanged intervals are not reported. Changes are ordered
+// by old version, followed by added intervals.
+func Diff_Key(before, after TimeIntervals, key func(ti *TimeInterval) string) *ScheduleDiff {
+
+	d := &ScheduleDiff{Changes: []IntervalChange{}}
+
+	oldSet := NewIntervalSet(before...)
+	newSet := NewIntervalSet(after...)
+	d.Added = newSet.Subtract(oldSet)
+	d.Removed = oldSet.Subtract(newSet)
+
+	// New intervals by key, in order
+	byKey := map[string][]*TimeInterval{}
+	for _, ti := range after {
+		k := key(ti)
+		byKey[k] = append(byKey[k], ti)
+	}
+
+	matched := map[*TimeInterval]bool{}
+	for _, old := range before {
+
+		k := key(old)
+		if len(byKey[k]) == 0 {
+			d.Changes = append(d.Changes, IntervalChange{Old: old, Kinds: []string{DIFF_REMOVED}})
+			continue
+		}
+
+		cur := byKey[k][0]
+		byKey[k] = byKey[k][1:]
+		matched[cur] = true
+
+		if kinds := changeKinds(old, cur); len(kinds) != 0 {
+			d.Changes = append(d.Changes, IntervalChange{Old: old, New: cur, Kinds: kinds})
+		}
+	}
+
+	for _, ti := range after {
+		if !matched[ti] {
+			d.Changes = append(d.Changes, IntervalChange{New: ti, Kinds: []string{DIFF_ADDED}})
+		}
+	}
+
+	return d
+}
+
+// Returns kinds of changes between matched intervals,
+// interval both moved and resized is reported as both.
+func changeKinds(old, cur *TimeInterval) (kinds []string) {
+
+	if !cur.Ts.Equal(old.Ts) {
+		kinds = append(kinds, DIFF_MOVED)
+	}
+
+	switch {
+	case cur.Len() > old.Len():
+		kinds = append(kinds, DIFF_EXTENDED)
+	case cur.Len() < old.Len():
+		kinds = append(kinds, DIFF_SHORTENED)
+	}
+
+	if cur.Name != old.Name {
+		kinds = append(kinds, DIFF_RENAMED)
+	}
+
+	return
+}
+
+//------------------------------------------------------------
+// Diff qualities
+//------------------------------------------------------------
+
+// IsEmpty checks if versions are same.
+func (d *ScheduleDiff) IsEmpty() bool {
+	return len(d.Changes) == 0 && d.Added.IsEmpty() && d.Removed.IsEmpty()
+}
+
+// String draws old and new intervals of each change and
+// changed coverage, old ones marked with "-", new with "+".
+func (d *ScheduleDiff) String() string {
+	return d.String_Runtime(defaultRuntime())
+}
+
+// String_Runtime draws diff using runtime settings.
+func (d *ScheduleDiff) String_Runtime(rt *Runtime) string {
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Diff: %v change(s), +%v / -%v covered\n",
+		len(d.Changes), d.Added.TotalLen(), d.Removed.TotalLen()))
+
+	if d.IsEmpty() {
+		return buf.String()
+	}
+
+	// Copies are drawn, so that source indexes are untouched
+	tis := TimeIntervals{}
+	add := func(ti *TimeInterval, name string) {
+		c := ti.Clone()
+		c.Name = name
+		tis = append(tis, c)
+	}
+
+	for _, ch := range d.Changes {
+		kinds := " (" + strings.Join(ch.Kinds, ",") + ")"
+		if ch.Old != nil {
+			add(ch.Old, "- "+ch.Old.Name+kinds)
+		}
+		if ch.New != nil {
+			add(ch.New, "+ "+ch.New.Name+kinds)
+		}
+	}
+	for _, ti := range d.Added.tis {
+		add(ti, "+ covered")
+	}
+	for _, ti := range d.Removed.tis {
+		add(ti, "- covered")
+	}
+
+	buf.WriteString(NewTimeIntervals(tis...).String_Runtime(rt))
+	return buf.String()
+}
@@ -0,0 +1,233 @@
+// Overlap graph of intervals: vertices are intervals,
+// edges connect intervals that overlap or are separated
+// by gap not longer than runtime tolerance. Intervals of
+// zero or negative length cover no time and are adjacent
+// to none, each forming its own component.
+//
+//	Source:      [_a_]  [_c_]      [_e_]
+//	               [___b___]
+//	Components:  {a, b, c}         {e}
+package intvl
+
+import (
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Overlap graph model
+//------------------------------------------------------------
+
+// OverlapGraph holds adjacency lists of intervals
+// by their position within source, ascending.
+type OverlapGraph struct {
+	Edges [][]int
+}
+
+// Component is a connected group of intervals.
+type Component struct {
+	Idxs      []int         // positions within source, ascending
+	Intervals TimeIntervals // source intervals sorted by Ts
+	Span      *TimeInterval // from earliest start to latest end
+}
+
+//------------------------------------------------------------
+// Graph construction
+//------------------------------------------------------------
+
+// OverlapGraph builds overlap graph using default runtime.
+func (tis TimeIntervals) OverlapGraph() OverlapGraph {
+	return tis.OverlapGraph_Runtime(defaultRuntime())
+}
+
+// OverlapGraph_Runtime builds overlap graph according to
+// runtime boundary semantics and tolerance.
+func (tis TimeIntervals) OverlapGraph_Runtime(rt *Runtime) (g OverlapGraph) {
+
+	g.Edges = make([][]int, len(tis))
+	order := tis.orderByTs()
+
+	for a, i := range order {
+		for _, j := range order[a+1:] {
+
+			// Later ones start even further
+			if tis[j].Ts.Sub(tis[i].Te) > rt.Tolerance {
+				break
+			}
+
+			if rt.isAdjacent(tis[i], tis[j]) {
+				g.Edges[i] = append(g.Edges[i], j)
+				g.Edges[j] = append(g.Edges[j], i)
+			}
+		}
+	}
+
+	for _, edges := range g.Edges {
+		sort.Ints(edges)
+	}
+
+	return
+}
+
+// Checks if intervals overlap or are separated
+// by gap within tolerance. Empty intervals are never adjacent.
+func (rt *Runtime) isAdjacent(a, b *TimeInterval) bool {
+
+	if a.Len() <= 0 || b.Len() <= 0 {
+		return false
+	}
+
+	if rt.IsOverlap(a, b) {
+		return true
+	}
+	if rt.Tolerance <= 0 {
+		return false
+	}
+
+	gap := b.Ts.Sub(a.Te)
+	if a.Ts.After(b.Ts) {
+		gap = a.Ts.Sub(b.Te)
+	}
+	return gap <= rt.Tolerance
+}
+
+//------------------------------------------------------------
+// Graph queries
+//------------------------------------------------------------
+
+// Components returns connected components using default runtime.
+func (tis TimeIntervals) Components() []Component {
+	return tis.Components_Runtime(defaultRuntime())
+}
+
+// Components_Runtime returns connected components of overlap
+// graph according to runtime, sorted by span start.
+func (tis TimeIntervals) Components_Runtime(rt *Runtime) (comps []Component) {
+
+	g := tis.OverlapGraph_Runtime(rt)
+	seen := make([]bool, len(tis))
+
+	comps = []Component{}
+	for _, start := range tis.orderByTs() {
+
+		if seen[start] {
+			continue
+		}
+
+		// Collect component breadth first
+		comp := Component{}
+		seen[start] = true
+		queue := []int{start}
+		for len(queue) != 0 {
+			i := queue[0]
+			queue = queue[1:]
+			comp.Idxs = append(comp.Idxs, i)
+
+			for _, j := range g.Edges[i] {
+				if !seen[j] {
+					seen[j] = true
+					queue = append(queue, j)
+				}
+			}
+		}
+
+		sort.Ints(comp.Idxs)
+		for _, i := range comp.Idxs {
+			comp.Intervals = append(comp.Intervals, tis[i])
+		}
+		sort.Stable(TimeIntervals_ByTs(comp.Intervals))
+		comp.Span = comp.Intervals.Span()
+
+		comps = append(comps, comp)
+	}
+
+	return
+}
+
+// MaxClique returns largest set of intervals all adjacent
+// to each other using default runtime.
+func (tis TimeIntervals) MaxClique() TimeIntervals {
+	return tis.MaxClique_Runtime(defaultRuntime())
+}
+
+// MaxClique_Runtime returns largest set of intervals all adjacent
+// to each other according to runtime, sorted by Ts. In interval
+// graph such set is active at a single point in time, with each
+// interval's end extended by tolerance. Empty intervals are
+// adjacent to none and are skipped.
+func (tis TimeIntervals) MaxClique_Runtime(rt *Runtime) (clique TimeIntervals) {
+
+	type event struct {
+		t     time.Time
+		idx   int
+		start bool
+	}
+
+	events := make([]event, 0, len(tis)*2)
+	for i, ti := range tis {
+
+		// Adjacent to none, see isAdjacent
+		if ti.Len() <= 0 {
+			continue
+		}
+
+		events = append(events,
+			event{t: ti.Ts, idx: i, start: true},
+			event{t: ti.Te.Add(rt.Tolerance), idx: i},
+		)
+	}
+
+	// At equal times closed or tolerant ends come after starts
+	touching := rt.Boundary == BOUNDS_CLOSED || rt.Tolerance > 0
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].t.Equal(events[j].t) && events[i].start != events[j].start {
+			return events[i].start == touching
+		}
+		return events[i].t.Before(events[j].t)
+	})
+
+	active := map[int]bool{}
+	var best []int
+	for _, e := range events {
+
+		if !e.start {
+			delete(active, e.idx)
+			continue
+		}
+
+		active[e.idx] = true
+		if len(active) > len(best) {
+			best = best[:0]
+			for i := range active {
+				best = append(best, i)
+			}
+		}
+	}
+
+	sort.Ints(best)
+	clique = TimeIntervals{}
+	for _, i := range best {
+		clique = append(clique, tis[i])
+	}
+	sort.Stable(TimeIntervals_ByTs(clique))
+
+	return
+}
+
+//------------------------------------------------------------
+// Helpers
+//------------------------------------------------------------
+
+// Returns positions of intervals sorted by Ts.
+func (tis TimeIntervals) orderByTs() []int {
+
+	order := make([]int, len(tis))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return tis[order[i]].Ts.Before(tis[order[j]].Ts)
+	})
+
+	return order
+}
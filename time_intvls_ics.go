@@ -0,0 +1,552 @@
+// TimeIntervals import and export in iCalendar (RFC 5545) format.
+//
+// Each interval maps to a VEVENT:
+//
+//	Ts   -> DTSTART
+//	Te   -> DTEND
+//	Name -> SUMMARY
+//
+// Times are written with TZID of their location if location
+// is a known IANA zone, otherwise converted to UTC.
+// Reader expands simple recurrences (RRULE with FREQ, INTERVAL,
+// COUNT and UNTIL) into separate intervals.
+package intvl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+// Constants
+//------------------------------------------------------------
+
+// Protects from endless loops on rules that never produce occurrences.
+const icsRecurIterLimit = 100000
+
+//------------------------------------------------------------
+// Export
+//------------------------------------------------------------
+
+// WriteICS writes intervals as iCalendar VEVENTs.
+func (tis TimeIntervals) WriteICS(w io.Writer) error {
+
+	var buf bytes.Buffer
+	stamp := time.Now().UTC().Format(ICS_LAYOUT_UTC)
+
+	icsWriteLine(&buf, "BEGIN:VCALENDAR")
+	icsWriteLine(&buf, "VERSION:2.0")
+	icsWriteLine(&buf, "PRODID:"+ICS_PRODID)
+
+	uids := map[string]int{}
+	for _, ti := range tis {
+		icsWriteLine(&buf, "BEGIN:VEVENT")
+		icsWriteLine(&buf, "UID:"+icsUID(ti, uids))
+		icsWriteLine(&buf, "DTSTAMP:"+stamp)
+		icsWriteLine(&buf, "DTSTART"+icsFormatTime(ti.Ts))
+		icsWriteLine(&buf, "DTEND"+icsFormatTime(ti.Te))
+		if ti.Name != "" {
+			icsWriteLine(&buf, "SUMMARY:"+icsEscape(ti.Name))
+		}
+		icsWriteLine(&buf, "END:VEVENT")
+	}
+
+	icsWriteLine(&buf, "END:VCALENDAR")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Returns UID derived from Name, Ts and Te, so that it stays
+// same when other intervals are added or removed. Identical
+// intervals are numbered in order of appearance.
+func icsUID(ti *TimeInterval, seen map[string]int) string {
+
+	h := fnv.New64a()
+	h.Write([]byte(ti.Name))
+	h.Write([]byte(ti.Ts.UTC().Format(time.RFC3339Nano)))
+	h.Write([]byte(ti.Te.UTC().Format(time.RFC3339Nano)))
+
+	uid := fmt.Sprintf("%s-%016x", ti.Ts.UTC().Format(ICS_LAYOUT_UTC), h.Sum64())
+	if n := seen[uid]; n != 0 {
+		seen[uid]++
+		return fmt.Sprintf("%s-%d@intvl", uid, n)
+	}
+	seen[uid] = 1
+	return uid + "@intvl"
+}
+
+// Writes content line folding it at ICS_LINE_MAX octets.
+func icsWriteLine(buf *bytes.Buffer, line string) {
+
+	max := ICS_LINE_MAX
+	for len(line) > max {
+
+		// Don't cut multi-byte characters
+		cut := max
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+
+		// Continuation lines start with a space
+		max = ICS_LINE_MAX - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// Formats time as property parameters and value,
+// for example ";TZID=Europe/Berlin:20150315T120000".
+func icsFormatTime(t time.Time) string {
+
+	name := t.Location().String()
+	if name == "UTC" || name == "" || name == "Local" {
+		return ":" + t.UTC().Format(ICS_LAYOUT_UTC)
+	}
+
+	// Only zones that reader is able to load back
+	if _, err := time.LoadLocation(name); err != nil {
+		return ":" + t.UTC().Format(ICS_LAYOUT_UTC)
+	}
+
+	return ";TZID=" + name + ":" + t.Format(ICS_LAYOUT_LOCAL)
+}
+
+// Escapes TEXT value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// Unescapes TEXT value.
+func icsUnescape(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
+
+//------------------------------------------------------------
+// Import
+//------------------------------------------------------------
+
+// Property of iCalendar content line.
+type icsProp struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// ReadICS reads VEVENTs into time intervals sorted by start.
+// Location of each time is preserved from its TZID.
+// Recurring events are expanded into one interval per occurrence,
+// unbounded recurrences are limited to ICS_RECUR_MAX occurrences.
+func ReadICS(r io.Reader) (tis TimeIntervals, err error) {
+
+	var props []icsProp
+	if props, err = icsReadProps(r); err != nil {
+		return
+	}
+
+	var event []icsProp
+	inEvent := false
+
+	for _, prop := range props {
+		switch {
+
+		case prop.name == "BEGIN" && prop.value == "VEVENT":
+			inEvent = true
+			event = nil
+
+		case prop.name == "END" && prop.value == "VEVENT":
+			if !inEvent {
+				err = fmt.Errorf("ICS line %v: END:VEVENT without BEGIN", prop.line)
+				return
+			}
+			inEvent = false
+
+			var occurs []*TimeInterval
+			if occurs, err = icsEvent(event); err != nil {
+				return
+			}
+			tis = append(tis, occurs...)
+
+		case inEvent:
+			event = append(event, prop)
+		}
+	}
+
+	if inEvent {
+		err = errors.New("ICS: VEVENT is not terminated")
+		return
+	}
+
+	tis = NewTimeIntervals_KeepLocation(tis...)
+	return
+}
+
+// Reads and unfolds all content lines.
+func icsReadProps(r io.Reader) (props []icsProp, err error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var line string
+	lineNum, startNum := 0, 0
+
+	flush := func() error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		prop, err := icsParseProp(line, startNum)
+		if err != nil {
+			return err
+		}
+		props = append(props, prop)
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		// Folded continuation
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+
+		if err = flush(); err != nil {
+			return
+		}
+		line = text
+		startNum = lineNum
+	}
+
+	if err = scanner.Err(); err != nil {
+		return
+	}
+
+	err = flush()
+	return
+}
+
+// Parses content line "NAME;PARAM=VAL;PARAM=VAL:value".
+func icsParseProp(line string, num int) (prop icsProp, err error) {
+
+	// Find value separator, skipping quoted parameter values
+	colon := -1
+	quoted := false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+
+	if colon == -1 {
+		err = fmt.Errorf("ICS line %v: content line without ':': %v", num, line)
+		return
+	}
+
+	prop.line = num
+	prop.value = line[colon+1:]
+	prop.params = map[string]string{}
+
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			err = fmt.Errorf("ICS line %v: invalid parameter: %v", num, part)
+			return
+		}
+		prop.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+
+	return
+}
+
+// Converts VEVENT properties into one or more intervals.
+func icsEvent(props []icsProp) (tis []*TimeInterval, err error) {
+
+	var dtstart, dtend, duration, summary, rrule *icsProp
+	for i := range props {
+		prop := &props[i]
+		switch prop.name {
+		case "DTSTART":
+			dtstart = prop
+		case "DTEND":
+			dtend = prop
+		case "DURATION":
+			duration = prop
+		case "SUMMARY":
+			summary = prop
+		case "RRULE":
+			rrule = prop
+		}
+	}
+
+	if dtstart == nil {
+		err = errors.New("ICS: VEVENT without DTSTART")
+		return
+	}
+
+	var ts, te time.Time
+	var isDate bool
+	if ts, isDate, err = icsParseTime(dtstart); err != nil {
+		return
+	}
+
+	// End is either explicit, or start + duration,
+	// or following day for all-day events
+	switch {
+	case dtend != nil:
+		if te, _, err = icsParseTime(dtend); err != nil {
+			return
+		}
+	case duration != nil:
+		var dur time.Duration
+		if dur, err = icsParseDuration(duration.value); err != nil {
+			err = fmt.Errorf("ICS line %v: %v", duration.line, err)
+			return
+		}
+		te = ts.Add(dur)
+	case isDate:
+		te = ts.AddDate(0, 0, 1)
+	default:
+		te = ts
+	}
+
+	if te.Before(ts) {
+		err = fmt.Errorf("ICS line %v: DTEND is before DTSTART", dtstart.line)
+		return
+	}
+
+	ti := &TimeInterval{Ts: ts, Te: te}
+	if summary != nil {
+		ti.Name = icsUnescape(summary.value)
+	}
+
+	if rrule == nil {
+		tis = append(tis, ti)
+		return
+	}
+
+	if tis, err = icsExpand(ti, rrule); err != nil {
+		err = fmt.Errorf("ICS line %v: %v", rrule.line, err)
+	}
+	return
+}
+
+// Parses DATE or DATE-TIME property value in its TZID location.
+func icsParseTime(prop *icsProp) (t time.Time, isDate bool, err error) {
+
+	loc := time.UTC
+	if tzid, ok := prop.params["TZID"]; ok {
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			err = fmt.Errorf("ICS line %v: unknown TZID: %v", prop.line, tzid)
+			return
+		}
+	}
+
+	value := prop.value
+	switch {
+	case prop.params["VALUE"] == "DATE" || len(value) == len(ICS_LAYOUT_DATE):
+		isDate = true
+		t, err = time.ParseInLocation(ICS_LAYOUT_DATE, value, loc)
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(ICS_LAYOUT_UTC, value)
+	default:
+		t, err = time.ParseInLocation(ICS_LAYOUT_LOCAL, value, loc)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("ICS line %v: unparseable %v: %v", prop.line, prop.name, value)
+	}
+	return
+}
+
+// Parses RFC 5545 duration like "PT1H30M", "P1D", "P2W", "-PT15M".
+func icsParseDuration(s string) (dur time.Duration, err error) {
+
+	str := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(str, "-"):
+		sign = -1
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	if !strings.HasPrefix(str, "P") || len(str) < 3 {
+		err = errors.New("Unparseable DURATION: " + s)
+		return
+	}
+	str = str[1:]
+
+	inTime := false
+	num := ""
+	for _, c := range str {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		n, e := strconv.Atoi(num)
+		if e != nil {
+			err = errors.New("Unparseable DURATION: " + s)
+			return
+		}
+		num = ""
+
+		var unit time.Duration
+		switch {
+		case c == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case c == 'H' && inTime:
+			unit = time.Hour
+		case c == 'M' && inTime:
+			unit = time.Minute
+		case c == 'S' && inTime:
+			unit = time.Second
+		default:
+			err = errors.New("Unparseable DURATION: " + s)
+			return
+		}
+		dur += time.Duration(n) * unit
+	}
+
+	if num != "" {
+		err = errors.New("Unparseable DURATION: " + s)
+		return
+	}
+
+	dur *= sign
+	return
+}
+
+// Expands recurrence rule into intervals of same length as ti.
+// Supported rule parts: FREQ, INTERVAL, COUNT, UNTIL, WKST.
+func icsExpand(ti *TimeInterval, rrule *icsProp) (tis []*TimeInterval, err error) {
+
+	var freq string
+	interval := 1
+	count := -1
+	var until time.Time
+
+	for _, part := range strings.Split(rrule.value, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			err = errors.New("Unparseable RRULE part: " + part)
+			return
+		}
+
+		switch strings.ToUpper(kv[0]) {
+		case "FREQ":
+			freq = strings.ToUpper(kv[1])
+		case "INTERVAL":
+			if interval, err = strconv.Atoi(kv[1]); err != nil || interval < 1 {
+				err = errors.New("Invalid RRULE INTERVAL: " + kv[1])
+				return
+			}
+		case "COUNT":
+			if count, err = strconv.Atoi(kv[1]); err != nil || count < 0 {
+				err = errors.New("Invalid RRULE COUNT: " + kv[1])
+				return
+			}
+		case "UNTIL":
+			p := &icsProp{name: "UNTIL", value: kv[1], params: map[string]string{}}
+			if until, _, err = icsParseTime(p); err != nil {
+				return
+			}
+			// Date-only UNTIL includes the whole day
+			if len(kv[1]) == len(ICS_LAYOUT_DATE) {
+				until = time.Date(until.Year(), until.Month(), until.Day(),
+					23, 59, 59, 0, ti.Ts.Location())
+			}
+		case "WKST":
+			// No effect without BYDAY
+		default:
+			err = errors.New("Unsupported RRULE part: " + part)
+			return
+		}
+	}
+
+	// Next occurrence start for i-th step
+	var step func(i int) time.Time
+	isCalendar := freq == "MONTHLY" || freq == "YEARLY"
+	switch freq {
+	case "MINUTELY":
+		step = func(i int) time.Time { return ti.Ts.Add(time.Duration(i*interval) * time.Minute) }
+	case "HOURLY":
+		step = func(i int) time.Time { return ti.Ts.Add(time.Duration(i*interval) * time.Hour) }
+	case "DAILY":
+		step = func(i int) time.Time { return ti.Ts.AddDate(0, 0, i*interval) }
+	case "WEEKLY":
+		step = func(i int) time.Time { return ti.Ts.AddDate(0, 0, 7*i*interval) }
+	case "MONTHLY":
+		step = func(i int) time.Time { return ti.Ts.AddDate(0, i*interval, 0) }
+	case "YEARLY":
+		step = func(i int) time.Time { return ti.Ts.AddDate(i*interval, 0, 0) }
+	default:
+		err = errors.New("Unsupported RRULE FREQ: " + freq)
+		return
+	}
+
+	max := count
+	if max == -1 {
+		max = ICS_RECUR_MAX
+	}
+
+	dur := ti.Len()
+	for i := 0; len(tis) < max && i < icsRecurIterLimit; i++ {
+
+		ts := step(i)
+		if !until.IsZero() && ts.After(until) {
+			break
+		}
+
+		// Months without such day are skipped, eg Feb 30
+		if isCalendar && ts.Day() != ti.Ts.Day() {
+			continue
+		}
+
+		occur := ti.Clone()
+		occur.Ts = ts
+		occur.Te = ts.Add(dur)
+		tis = append(tis, occur)
+	}
+
+	return
+}
@@ -0,0 +1,154 @@
+// Temporal join of two sorted time intervals.
+//
+//	Left:      [__d1__]        [__d2__]     [d3]
+//	Right:        [___i1___]     [i2]
+//	Inner:     (d1,i1) (d2,i2)
+//	Anti:      d3
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Join model
+//------------------------------------------------------------
+
+// JoinQuery describes temporal join.
+type JoinQuery struct {
+	Mode      string        // one of JOIN_*, other values panic
+	Lookback  time.Duration // right intervals ending this long before left start also match
+	Lookahead time.Duration // right intervals starting this long after left end also match
+}
+
+// JoinPair is a left interval with matched right one.
+// For unmatched left intervals Right is nil and RightIdx is -1.
+type JoinPair struct {
+	Left     *TimeInterval
+	Right    *TimeInterval
+	LeftIdx  int           // position within left intervals
+	RightIdx int           // position within right intervals
+	Overlap  time.Duration // length of common time, 0 if matched by lookback/lookahead
+	Relation string        // Allen relation of left to right, one of RELATION_*
+}
+
+// NewJoinQuery creates join query of given mode.
+func NewJoinQuery(mode string) *JoinQuery {
+	return &JoinQuery{Mode: mode}
+}
+
+//------------------------------------------------------------
+// Join
+//------------------------------------------------------------
+
+// Join matches intervals against other intervals using default
+// runtime boundary semantics. Both must be sorted by Ts.
+// Pairs are ordered by left intervals, then by right ones.
+func (tis TimeIntervals) Join(other TimeIntervals, q *JoinQuery) []JoinPair {
+	return tis.Join_Runtime(defaultRuntime(), other, q)
+}
+
+// Join_Runtime matches intervals against other intervals
+// according to runtime boundary semantics. Merge sweep takes
+// time proportional to number of intervals plus number of matches.
+// Panics if query mode is not one of JOIN_*.
+func (tis TimeIntervals) Join_Runtime(rt *Runtime, other TimeIntervals, q *JoinQuery) (pairs []JoinPair) {
+
+	var keepMatched, keepUnmatched bool
+	switch q.Mode {
+	case JOIN_INNER:
+		keepMatched = true
+	case JOIN_LEFT:
+		keepMatched, keepUnmatched = true, true
+	case JOIN_ANTI:
+		keepUnmatched = true
+	default:
+		panic("Invalid JoinQuery: unknown mode " + q.Mode)
+	}
+
+	// Left intervals widened by lookback and lookahead
+	windows := make(TimeIntervals, len(tis))
+	for i, ti := range tis {
+		windows[i] = &TimeInterval{Ts: ti.Ts.Add(-q.Lookback), Te: ti.Te.Add(q.Lookahead)}
+	}
+
+	matches := make([][]int, len(tis))
+	match := func(i, j int) {
+		if rt.IsOverlap(windows[i], other[j]) {
+			matches[i] = append(matches[i], j)
+		}
+	}
+
+	// Started and not yet ended intervals of both sides
+	var activeL, activeR []int
+	i, j := 0, 0
+	for i < len(windows) || j < len(other) {
+
+		if j == len(other) || i < len(windows) && !windows[i].Ts.After(other[j].Ts) {
+			t := windows[i].Ts
+			activeR = pruneEnded(rt, activeR, other, t)
+			for _, k := range activeR {
+				match(i, k)
+			}
+			activeL = append(activeL, i)
+			i++
+			continue
+		}
+
+		t := other[j].Ts
+		activeL = pruneEnded(rt, activeL, windows, t)
+		for _, k := range activeL {
+			match(k, j)
+		}
+		activeR = append(activeR, j)
+		j++
+	}
+
+	pairs = []JoinPair{}
+	for i, left := range tis {
+
+		if len(matches[i]) == 0 {
+			if keepUnmatched {
+				pairs = append(pairs, JoinPair{Left: left, LeftIdx: i, RightIdx: -1})
+			}
+			continue
+		}
+
+		if !keepMatched {
+			continue
+		}
+
+		for _, j := range matches[i] {
+			right := other[j]
+			pairs = append(pairs, JoinPair{
+				Left:     left,
+				Right:    right,
+				LeftIdx:  i,
+				RightIdx: j,
+				Overlap:  overlapLen(left, right),
+				Relation: left.Relation(right),
+			})
+		}
+	}
+
+	return
+}
+
+// Removes positions of intervals ended by t.
+func pruneEnded(rt *Runtime, active []int, tis TimeIntervals, t time.Time) []int {
+
+	kept := active[:0]
+	for _, k := range active {
+		if !rt.isEndedBy(tis[k], t) {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
+
+// Returns length of time common to both intervals.
+func overlapLen(a, b *TimeInterval) time.Duration {
+
+	if d := earlierOf(a.Te, b.Te).Sub(laterOf(a.Ts, b.Ts)); d > 0 {
+		return d
+	}
+	return 0
+}
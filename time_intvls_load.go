@@ -0,0 +1,178 @@
+// Load profile sums weights of active intervals over time.
+//
+//	Source:  [__2__]
+//	            [__1__]   [3_]
+//	Load:    2  3  1  0   3
+package intvl
+
+import (
+	"math"
+	"time"
+)
+
+//------------------------------------------------------------
+// Load profile model
+//------------------------------------------------------------
+
+// LoadStep is a period of constant load.
+type LoadStep struct {
+	Ts   time.Time
+	Te   time.Time
+	Load float64
+}
+
+// Len returns length of step.
+func (s LoadStep) Len() time.Duration {
+	return s.Te.Sub(s.Ts)
+}
+
+// LoadProfile is a piecewise-constant sum of weights, steps
+// are contiguous and sorted, adjacent steps differ in load.
+// Steps cover time from earliest start to latest end,
+// including steps of zero load between intervals.
+type LoadProfile []LoadStep
+
+//------------------------------------------------------------
+// Load profile creation
+//------------------------------------------------------------
+
+// Load sweeps through start and end points of intervals
+// and returns profile of summed interval weights.
+func (tis TimeIntervals) Load() LoadProfile {
+	return tis.sweep(func(ti *TimeInterval) float64 { return ti.Weight })
+}
+
+// Sweeps through points of intervals summing weights
+// of active intervals. Points at equal times are applied
+// together, so touching intervals don't overlap.
+func (tis TimeIntervals) sweep(weight func(ti *TimeInterval) float64) (p LoadProfile) {
+
+	p = LoadProfile{}
+
+	load := 0.0
+	active := 0
+	var last time.Time
+	tis.sweepPoints(func(t time.Time, group TimePoints) {
+
+		last = t
+
+		for _, point := range group {
+			w := weight(tis[point.Idx])
+			switch point.Type {
+			case "s":
+				load += w
+				active++
+			case "e":
+				load -= w
+				active--
+			}
+		}
+
+		// Drop rounding errors when nothing is active
+		if active == 0 {
+			load = 0
+		}
+
+		// Close previous step
+		if n := len(p); n != 0 {
+			if isEqualLoad(p[n-1].Load, load) {
+				return
+			}
+			p[n-1].Te = t
+		}
+
+		p = append(p, LoadStep{Ts: t, Load: load})
+	})
+
+	// Close step reaching last end, such as zero load of trailing
+	// zero weight intervals, or drop step opened by last end
+	if n := len(p); n != 0 && p[n-1].Te.IsZero() {
+		if p[n-1].Ts.Before(last) {
+			p[n-1].Te = last
+		} else {
+			p = p[:n-1]
+		}
+	}
+
+	return
+}
+
+//------------------------------------------------------------
+// Load profile queries
+//------------------------------------------------------------
+
+// Peak returns first step with highest load
+// or zero step if profile is empty.
+func (p LoadProfile) Peak() (peak LoadStep) {
+
+	for i, s := range p {
+		if i == 0 || s.Load > peak.Load {
+			peak = s
+		}
+	}
+	return
+}
+
+// At returns load at time t.
+// Step includes its start and excludes its end.
+func (p LoadProfile) At(t time.Time) float64 {
+
+	for _, s := range p {
+		if !t.Before(s.Ts) && t.Before(s.Te) {
+			return s.Load
+		}
+	}
+	return 0
+}
+
+// Integral returns load integrated over time measured in units,
+// e.g. core-hours for load in cores and unit of time.Hour.
+func (p LoadProfile) Integral(unit time.Duration) (sum float64) {
+
+	for _, s := range p {
+		sum += s.Load * float64(s.Len()) / float64(unit)
+	}
+	return
+}
+
+// OverCapacity returns regions where load exceeds capacity,
+// adjacent regions are merged. Capacity is sampled at start
+// of each step and, if dt is positive, every dt within step.
+func (p LoadProfile) OverCapacity(capacity func(t time.Time) float64, dt time.Duration) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	add := func(ts, te time.Time) {
+		if n := len(res); n != 0 && res[n-1].Te.Equal(ts) {
+			res[n-1].Te = te
+			return
+		}
+		res = append(res, &TimeInterval{Ts: ts, Te: te, idx: len(res)})
+	}
+
+	for _, s := range p {
+		for ts := s.Ts; ts.Before(s.Te); {
+
+			te := s.Te
+			if dt > 0 {
+				te = earlierOf(te, ts.Add(dt))
+			}
+
+			if s.Load > capacity(ts) {
+				add(ts, te)
+			}
+			ts = te
+		}
+	}
+
+	return
+}
+
+// Capacity returns capacity function of constant value.
+func Capacity(value float64) func(t time.Time) float64 {
+	return func(t time.Time) float64 { return value }
+}
+
+// Returns true if loads are equal within float precision.
+func isEqualLoad(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
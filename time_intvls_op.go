@@ -9,12 +9,30 @@ package intvl
 // Returns new time intervals marked with results of
 // analysis as meta information.
 func (tis TimeIntervals) AnalyzeRelativeTo(bounds *TimeInterval) (res TimeIntervals) {
+	return tis.AnalyzeRelativeTo_Runtime(defaultRuntime(), bounds)
+}
+
+// Gaps analyses time intervals within given bounds
+// using runtime settings: gaps not longer than
+// runtime tolerance are ignored.
+func (tis TimeIntervals) AnalyzeRelativeTo_Runtime(rt *Runtime, bounds *TimeInterval) (res TimeIntervals) {
 
 	// Find all gaps
 	gaps := NewTimeIntervals(bounds)
 
 	for _, ti := range tis {
-		gaps = gaps.Exclude(ti)
+		gaps = gaps.Exclude_Runtime(rt, ti)
+	}
+
+	// Drop gaps within tolerance
+	if rt.Tolerance > 0 {
+		kept := TimeIntervals{}
+		for _, ti := range gaps {
+			if ti.Len() > rt.Tolerance {
+				kept = append(kept, ti)
+			}
+		}
+		gaps = kept
 	}
 
 	// Special case:
@@ -78,17 +96,25 @@ func (tis TimeIntervals) AnalyzeRelativeTo(bounds *TimeInterval) (res TimeInterv
 			}
 		}
 	*/
-
-	return
 }
 
 // Excludes runs exlude of excl interval against
 // each of tis intervals and returns resulting modified tis.
 func (tis TimeIntervals) Exclude(excl *TimeInterval) (res TimeIntervals) {
+	return tis.exclude(defaultRuntime(), nil, excl)
+}
+
+// Exclude_Runtime runs exclude according to runtime boundary semantics.
+func (tis TimeIntervals) Exclude_Runtime(rt *Runtime, excl *TimeInterval) (res TimeIntervals) {
+	return tis.exclude(rt, nil, excl)
+}
+
+// Runs exclude using runtime and payload policy.
+func (tis TimeIntervals) exclude(rt *Runtime, p *PayloadPolicy, excl *TimeInterval) (res TimeIntervals) {
 
 	res = TimeIntervals{}
 	for _, ti := range tis {
-		rems := ti.Exclude(excl)
+		rems := ti.exclude(rt, p, excl)
 
 		// Extra bug protection:
 		// Remove intervals with zero length
@@ -108,8 +134,23 @@ func (tis TimeIntervals) Exclude(excl *TimeInterval) (res TimeIntervals) {
 // overs - parts of intervals that overlap with originals
 // and need to be deleted.
 // Name of each interval is preserved and can be used as meta data.
-// Each overlap name contains both names, comma-separated.
+// Each overlap name contains both names, comma-separated,
+// and its Data is first non-nil payload, see PayloadPolicy.
 func (tis TimeIntervals) AnalyzeOverlaps() (origs, dups, overs []*TimeInterval) {
+	return tis.AnalyzeOverlaps_Runtime(defaultRuntime())
+}
+
+// Analyzes intervals for duplicates and overlaps
+// using runtime settings: overlaps not longer than
+// runtime tolerance are ignored. With BOUNDS_CLOSED
+// touching intervals overlap at a point, reported
+// as overlap of zero length.
+func (tis TimeIntervals) AnalyzeOverlaps_Runtime(rt *Runtime) (origs, dups, overs []*TimeInterval) {
+	return tis.analyzeOverlaps(rt, nil)
+}
+
+// Analyzes intervals using runtime and payload policy.
+func (tis TimeIntervals) analyzeOverlaps(rt *Runtime, p *PayloadPolicy) (origs, dups, overs []*TimeInterval) {
 
 	nextOrigIdx := 0
 	for i, ti := range tis {
@@ -124,7 +165,7 @@ func (tis TimeIntervals) AnalyzeOverlaps() (origs, dups, overs []*TimeInterval)
 		for j, tiNext := range tis[i+1:] {
 
 			// Stop if next doesn't overlap
-			if tiNext.Ts.Sub(ti.Te) >= 0 {
+			if rt.isEndedBy(ti, tiNext.Ts) {
 				nextOrigIdx = i + j + 1
 
 				/*
@@ -154,9 +195,13 @@ func (tis TimeIntervals) AnalyzeOverlaps() (origs, dups, overs []*TimeInterval)
 			}
 
 			// Overlap ? Next starts before current ends
-			if ti.Te.Sub(tiNext.Ts) > 0 {
-				over := tiNext.TrimRight(ti.Te)
+			if rt.IsOverlap(ti, tiNext) {
+				over := p.cut(tiNext, tiNext.Ts, earlierOf(ti.Te, tiNext.Te))
+				if rt.Tolerance > 0 && over.Len() <= rt.Tolerance {
+					continue
+				}
 				over.Name = ti.Name + "," + tiNext.Name
+				over.Data = p.mergeData(p.cut(ti, over.Ts, over.Te).Data, over.Data)
 				overs = append(overs, over)
 
 				/*
@@ -0,0 +1,125 @@
+// Partitioning of intervals onto lanes, such as machines or rooms,
+// so that intervals within each lane don't overlap.
+//
+//	Source:    [_a_]  [__c__]
+//	             [__b__]  [_d_]
+//	Lane 0:    [_a_]  [__c__]
+//	Lane 1:      [__b__]  [_d_]
+package intvl
+
+import "container/heap"
+
+//------------------------------------------------------------
+// Partition model
+//------------------------------------------------------------
+
+// Partition holds assignment of intervals to lanes.
+// Lanes contain source intervals sorted by Ts,
+// intervals themselves are not modified.
+type Partition struct {
+	Lanes    []TimeIntervals
+	Lane     []int         // lane of each source interval by position, -1 if not placed
+	Unplaced TimeIntervals // intervals that didn't fit into any lane
+}
+
+//------------------------------------------------------------
+// Partitioning
+//------------------------------------------------------------
+
+// Partition assigns intervals to minimum number of lanes
+// according to default runtime boundary semantics.
+func (tis TimeIntervals) Partition() Partition {
+	return tis.partition(defaultRuntime(), -1)
+}
+
+// Partition_Lanes assigns intervals to at most n lanes.
+// Intervals are placed greedily by Ts into lane that
+// became free earliest, those that don't fit are reported
+// as unplaced.
+func (tis TimeIntervals) Partition_Lanes(n int) Partition {
+	return tis.partition(defaultRuntime(), n)
+}
+
+// Partition_Runtime assigns intervals to minimum
+// number of lanes according to runtime boundary semantics.
+func (tis TimeIntervals) Partition_Runtime(rt *Runtime) Partition {
+	return tis.partition(rt, -1)
+}
+
+// Greedy partitioning by Ts, limited to n lanes if n >= 0.
+func (tis TimeIntervals) partition(rt *Runtime, n int) (p Partition) {
+
+	p.Lanes = []TimeIntervals{}
+	p.Lane = make([]int, len(tis))
+	p.Unplaced = TimeIntervals{}
+
+	// Lanes by time they become free
+	free := &laneHeap{}
+	for _, i := range tis.orderByTs() {
+		ti := tis[i]
+
+		lane := -1
+		switch {
+		case free.Len() != 0 && rt.isEndedBy(free.peek(), ti.Ts):
+			lane = heap.Pop(free).(laneEnd).lane
+		case n < 0 || len(p.Lanes) < n:
+			lane = len(p.Lanes)
+			p.Lanes = append(p.Lanes, TimeIntervals{})
+		}
+
+		p.Lane[i] = lane
+		if lane == -1 {
+			p.Unplaced = append(p.Unplaced, ti)
+			continue
+		}
+
+		p.Lanes[lane] = append(p.Lanes[lane], ti)
+		heap.Push(free, laneEnd{ti: ti, lane: lane})
+	}
+
+	return
+}
+
+//------------------------------------------------------------
+// Lane heap
+//------------------------------------------------------------
+
+// Last interval of lane.
+type laneEnd struct {
+	ti   *TimeInterval
+	lane int
+}
+
+// Min-heap of lanes by end of last interval.
+type laneHeap []laneEnd
+
+func (h laneHeap) Len() int {
+	return len(h)
+}
+
+func (h laneHeap) Less(i, j int) bool {
+	if h[i].ti.Te.Equal(h[j].ti.Te) {
+		return h[i].lane < h[j].lane
+	}
+	return h[i].ti.Te.Before(h[j].ti.Te)
+}
+
+func (h laneHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *laneHeap) Push(x interface{}) {
+	*h = append(*h, x.(laneEnd))
+}
+
+func (h *laneHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Returns last interval of lane that becomes free first.
+func (h laneHeap) peek() *TimeInterval {
+	return h[0].ti
+}
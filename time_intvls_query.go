@@ -0,0 +1,289 @@
+// Point queries of time intervals.
+//
+// Intervals must be sorted by Ts, as created by NewTimeIntervals,
+// which also indexes them by Te, so that every query takes O(log n)
+// even when intervals overlap. Intervals must not be modified after
+// creation. Other sorted slices are not indexed and are scanned.
+// For many points use Assign, which answers all of them
+// in a single sweep.
+package intvl
+
+import (
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Time Intervals point queries
+//------------------------------------------------------------
+
+// At finds interval that contains t.
+// If several intervals contain t, the latest starting one is returned.
+//
+//	  [ a ]  [   b   ]  [ c ]
+//	------------|---------->
+//	            t  = b
+func (tis TimeIntervals) At(t time.Time) *TimeInterval {
+
+	if i := tis.at(defaultRuntime(), t); i != -1 {
+		return tis[i]
+	}
+	return nil
+}
+
+// Next finds first interval that starts after t.
+//
+//	  [ a ]  [   b   ]  [ c ]
+//	------------|---------->
+//	            t   next = c
+func (tis TimeIntervals) Next(t time.Time) *TimeInterval {
+
+	if i := tis.next(t); i != -1 {
+		return tis[i]
+	}
+	return nil
+}
+
+// Prev finds interval that ended before t,
+// the latest ending one if there are several.
+//
+//	  [ a ]  [   b   ]  [ c ]
+//	------------|---------->
+//	 prev = a   t
+func (tis TimeIntervals) Prev(t time.Time) *TimeInterval {
+
+	if i := tis.prev(defaultRuntime(), t); i != -1 {
+		return tis[i]
+	}
+	return nil
+}
+
+// Nearest finds interval that contains t or, if there is none,
+// the closest of previous and next intervals. Previous wins a tie.
+func (tis TimeIntervals) Nearest(t time.Time) *TimeInterval {
+
+	if i := tis.nearest(defaultRuntime(), t); i != -1 {
+		return tis[i]
+	}
+	return nil
+}
+
+// Returns number of intervals starting at or before t.
+func (tis TimeIntervals) started(t time.Time) int {
+	return sort.Search(len(tis), func(i int) bool {
+		return tis[i].Ts.After(t)
+	})
+}
+
+// Returns position of latest starting interval containing t or -1.
+func (tis TimeIntervals) at(rt *Runtime, t time.Time) int {
+
+	k := tis.started(t)
+	if ix := tis.index(); ix != nil {
+		return ix.latestNotEnded(rt, t, 1, 0, ix.size, k)
+	}
+
+	for i := k - 1; i >= 0; i-- {
+		if rt.Contains(tis[i], t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns position of first interval starting after t or -1.
+func (tis TimeIntervals) next(t time.Time) int {
+
+	if i := tis.started(t); i < len(tis) {
+		return i
+	}
+	return -1
+}
+
+// Returns position of latest ending interval ended before t or -1.
+func (tis TimeIntervals) prev(rt *Runtime, t time.Time) int {
+
+	// Ended ones come first in order of Te
+	if ix := tis.index(); ix != nil {
+		n := sort.Search(len(ix.byTe), func(r int) bool {
+			return !rt.isEndedBy(tis[ix.byTe[r]], t)
+		})
+		if n == 0 {
+			return -1
+		}
+		return ix.byTe[n-1]
+	}
+
+	res := -1
+	for i := tis.started(t) - 1; i >= 0; i-- {
+		ti := tis[i]
+		if rt.isEndedBy(ti, t) && (res == -1 || ti.Te.After(tis[res].Te)) {
+			res = i
+		}
+	}
+	return res
+}
+
+// Returns position of interval containing t or closest one or -1.
+func (tis TimeIntervals) nearest(rt *Runtime, t time.Time) int {
+
+	if i := tis.at(rt, t); i != -1 {
+		return i
+	}
+
+	return tis.closer(t, tis.prev(rt, t), tis.next(t))
+}
+
+// Returns whichever of prev and next positions is closer to t.
+func (tis TimeIntervals) closer(t time.Time, prev, next int) int {
+
+	switch {
+	case prev == -1:
+		return next
+	case next == -1:
+		return prev
+	case tis[next].Ts.Sub(t) < t.Sub(tis[prev].Te):
+		return next
+	}
+	return prev
+}
+
+//------------------------------------------------------------
+// Time Intervals point index
+//------------------------------------------------------------
+
+// Index of intervals sorted by Ts, held by first of them.
+type pointIndex struct {
+	tis   TimeIntervals // indexed intervals
+	byTe  []int         // positions in order of Te, ties by position
+	maxTe []time.Time   // tree of latest Te, node i has children 2i and 2i+1
+	size  int           // number of tree leaves, power of two
+}
+
+// Builds point index of intervals sorted by Ts.
+func (tis TimeIntervals) buildIndex() {
+
+	if len(tis) == 0 {
+		return
+	}
+
+	ix := &pointIndex{tis: tis, size: 1}
+
+	ix.byTe = make([]int, len(tis))
+	for i := range ix.byTe {
+		ix.byTe[i] = i
+	}
+	sort.SliceStable(ix.byTe, func(i, j int) bool {
+		return tis[ix.byTe[i]].Te.Before(tis[ix.byTe[j]].Te)
+	})
+
+	for ix.size < len(tis) {
+		ix.size *= 2
+	}
+	ix.maxTe = make([]time.Time, 2*ix.size)
+	for i, ti := range tis {
+		ix.maxTe[ix.size+i] = ti.Te
+	}
+	for i := ix.size - 1; i > 0; i-- {
+		ix.maxTe[i] = laterOf(ix.maxTe[2*i], ix.maxTe[2*i+1])
+	}
+
+	tis[0].index = ix
+}
+
+// Returns index if intervals are the ones indexed or nil.
+func (tis TimeIntervals) index() *pointIndex {
+
+	if len(tis) == 0 || tis[0].index == nil {
+		return nil
+	}
+
+	ix := tis[0].index
+	if len(ix.tis) != len(tis) || &ix.tis[0] != &tis[0] {
+		return nil
+	}
+	return ix
+}
+
+// Returns latest position before k of interval not ended
+// by t or -1, searching node covering positions [lo, hi).
+// Started interval not ended by t contains it.
+func (ix *pointIndex) latestNotEnded(rt *Runtime, t time.Time, node, lo, hi, k int) int {
+
+	if lo >= k || rt.isEndBefore(ix.maxTe[node], t) {
+		return -1
+	}
+	if hi-lo == 1 {
+		return lo
+	}
+
+	mid := (lo + hi) / 2
+	if i := ix.latestNotEnded(rt, t, 2*node+1, mid, hi, k); i != -1 {
+		return i
+	}
+	return ix.latestNotEnded(rt, t, 2*node, lo, mid, k)
+}
+
+//------------------------------------------------------------
+// Interval Set point queries
+//------------------------------------------------------------
+
+// Contains checks if t is covered by set.
+func (s IntervalSet) Contains(t time.Time) bool {
+	return s.at(defaultRuntime(), t) != -1
+}
+
+// At returns copy of member that contains t or nil.
+func (s IntervalSet) At(t time.Time) *TimeInterval {
+	return s.member(s.at(defaultRuntime(), t))
+}
+
+// Next returns copy of first member that starts after t or nil.
+func (s IntervalSet) Next(t time.Time) *TimeInterval {
+	return s.member(s.tis.next(t))
+}
+
+// Prev returns copy of last member that ended before t or nil.
+func (s IntervalSet) Prev(t time.Time) *TimeInterval {
+	return s.member(s.prev(defaultRuntime(), t))
+}
+
+// Nearest returns copy of member that contains t
+// or closest member or nil.
+func (s IntervalSet) Nearest(t time.Time) *TimeInterval {
+
+	rt := defaultRuntime()
+	if i := s.at(rt, t); i != -1 {
+		return s.member(i)
+	}
+	return s.member(s.tis.closer(t, s.prev(rt, t), s.tis.next(t)))
+}
+
+// Canonical members don't overlap, so only
+// the last one started may contain t.
+func (s IntervalSet) at(rt *Runtime, t time.Time) int {
+
+	if i := s.tis.started(t) - 1; i >= 0 && rt.Contains(s.tis[i], t) {
+		return i
+	}
+	return -1
+}
+
+// Canonical members are sorted by Te too,
+// so previous member is one of two last started.
+func (s IntervalSet) prev(rt *Runtime, t time.Time) int {
+
+	i := s.tis.started(t) - 1
+	if i >= 0 && !rt.isEndedBy(s.tis[i], t) {
+		i--
+	}
+	return i
+}
+
+// Returns copy of member at position i or nil if i is -1.
+func (s IntervalSet) member(i int) *TimeInterval {
+	if i == -1 {
+		return nil
+	}
+	return s.tis[i].Clone()
+}
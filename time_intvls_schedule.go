@@ -0,0 +1,104 @@
+// Interval scheduling: choosing subset of non-overlapping
+// intervals of maximum total weight or maximum count.
+//
+//	Source:    [___5___]
+//	             [_2_][_2_]  [_1_]
+//	Chosen:    [___5___]     [_1_]
+package intvl
+
+import "sort"
+
+//------------------------------------------------------------
+// Scheduling
+//------------------------------------------------------------
+
+// Schedule returns non-overlapping subset of intervals
+// with maximum total weight according to default runtime
+// boundary semantics. Weight is taken from function,
+// e.g. interval length, Weight field or payload.
+// Intervals of non-positive weight are never chosen.
+// Chosen intervals are source intervals sorted by Ts.
+func (tis TimeIntervals) Schedule(weight func(ti *TimeInterval) float64) (chosen TimeIntervals, total float64) {
+	return tis.Schedule_Runtime(defaultRuntime(), weight)
+}
+
+// Schedule_Runtime returns subset of maximum total weight
+// according to runtime boundary semantics.
+func (tis TimeIntervals) Schedule_Runtime(rt *Runtime, weight func(ti *TimeInterval) float64) (chosen TimeIntervals, total float64) {
+
+	sorted := tis.byTe()
+	n := len(sorted)
+
+	// Last compatible predecessor of each interval, -1 if none
+	pred := make([]int, n)
+	for j, ti := range sorted {
+		pred[j] = sort.Search(j, func(i int) bool {
+			return !rt.isEndedBy(sorted[i], ti.Ts)
+		}) - 1
+	}
+
+	// Best total of first j intervals
+	best := make([]float64, n+1)
+	for j, ti := range sorted {
+		best[j+1] = best[j]
+		if w := weight(ti); w > 0 && w+best[pred[j]+1] > best[j] {
+			best[j+1] = w + best[pred[j]+1]
+		}
+	}
+
+	// Walk back through decisions
+	chosen = TimeIntervals{}
+	for j := n - 1; j >= 0; {
+		if best[j+1] == best[j] {
+			j--
+			continue
+		}
+		chosen = append(chosen, sorted[j])
+		j = pred[j]
+	}
+
+	// Reverse into order of time
+	for i, j := 0, len(chosen)-1; i < j; i, j = i+1, j-1 {
+		chosen[i], chosen[j] = chosen[j], chosen[i]
+	}
+
+	total = best[n]
+	return
+}
+
+// Schedule_MaxCount returns largest non-overlapping subset
+// of intervals according to default runtime boundary
+// semantics, picking earliest ending intervals first.
+func (tis TimeIntervals) Schedule_MaxCount() TimeIntervals {
+	return tis.Schedule_MaxCount_Runtime(defaultRuntime())
+}
+
+// Schedule_MaxCount_Runtime returns largest subset
+// according to runtime boundary semantics.
+func (tis TimeIntervals) Schedule_MaxCount_Runtime(rt *Runtime) (chosen TimeIntervals) {
+
+	chosen = TimeIntervals{}
+	for _, ti := range tis.byTe() {
+		if n := len(chosen); n == 0 || rt.isEndedBy(chosen[n-1], ti.Ts) {
+			chosen = append(chosen, ti)
+		}
+	}
+
+	return
+}
+
+// Returns source intervals sorted by Te, then by Ts.
+func (tis TimeIntervals) byTe() TimeIntervals {
+
+	sorted := make(TimeIntervals, len(tis))
+	copy(sorted, tis)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Te.Equal(sorted[j].Te) {
+			return sorted[i].Ts.Before(sorted[j].Ts)
+		}
+		return sorted[i].Te.Before(sorted[j].Te)
+	})
+
+	return sorted
+}
@@ -0,0 +1,182 @@
+// Segments cut time line of overlapping intervals
+// into maximal non-overlapping pieces.
+//
+//	Source:    [___a___]
+//	               [___b___]
+//	Segments:  [ a |a,b| b ]
+package intvl
+
+import (
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Segment model
+//------------------------------------------------------------
+
+// Segment is a piece of time with constant set of active intervals.
+type Segment struct {
+	Ts    time.Time
+	Te    time.Time
+	Idxs  []int    // positions of active intervals within source, ascending
+	Names []string // distinct non-empty names of active intervals
+}
+
+// Segments are sorted and don't overlap.
+type Segments []Segment
+
+// Len returns length of segment.
+func (s Segment) Len() time.Duration {
+	return s.Te.Sub(s.Ts)
+}
+
+// Interval returns segment as interval named
+// by its names, comma-separated.
+func (s Segment) Interval() *TimeInterval {
+
+	ti := &TimeInterval{Ts: s.Ts, Te: s.Te}
+	for _, name := range s.Names {
+		ti.Name = joinNames(ti.Name, name)
+	}
+	return ti
+}
+
+// TimeIntervals returns segments as intervals, see Segment.Interval.
+func (segs Segments) TimeIntervals() TimeIntervals {
+
+	tis := make(TimeIntervals, len(segs))
+	for i, s := range segs {
+		tis[i] = s.Interval()
+		tis[i].idx = i
+	}
+	return tis
+}
+
+//------------------------------------------------------------
+// Segmentation
+//------------------------------------------------------------
+
+// Segments sweeps through start and end points of intervals
+// and returns elementary segments: a new segment starts at each
+// point where set of active intervals changes. Time covered by
+// no interval is not included, intervals of zero length are ignored.
+func (tis TimeIntervals) Segments() (segs Segments) {
+
+	segs = Segments{}
+	active := map[int]bool{}
+
+	tis.sweepPoints(func(t time.Time, group TimePoints) {
+
+		// Each group changes set of active intervals,
+		// zero-length ones are dropped by sweepPoints
+		if n := len(segs); n != 0 && segs[n-1].Te.IsZero() {
+			segs[n-1].Te = t
+		}
+
+		for _, p := range group {
+			switch p.Type {
+			case "s":
+				active[p.Idx] = true
+			case "e":
+				delete(active, p.Idx)
+			}
+		}
+
+		if len(active) != 0 {
+			segs = append(segs, tis.segment(t, active))
+		}
+	})
+
+	return
+}
+
+// Segments_MergeEqual returns segments where adjacent segments
+// with identical sets of names are merged together.
+//
+//	Source:    [___a___]   [_a_]
+//	                [_a_]
+//	Segments:  [___a___]   [_a_]
+func (tis TimeIntervals) Segments_MergeEqual() (segs Segments) {
+
+	segs = Segments{}
+	for _, s := range tis.Segments() {
+
+		n := len(segs)
+		if n == 0 || !segs[n-1].Te.Equal(s.Ts) || !isEqualNames(segs[n-1].Names, s.Names) {
+			segs = append(segs, s)
+			continue
+		}
+
+		last := &segs[n-1]
+		last.Te = s.Te
+		last.Idxs = unionIdxs(last.Idxs, s.Idxs)
+	}
+
+	return
+}
+
+// Creates open segment starting at t of active intervals.
+func (tis TimeIntervals) segment(t time.Time, active map[int]bool) Segment {
+
+	s := Segment{Ts: t}
+	for idx := range active {
+		s.Idxs = append(s.Idxs, idx)
+	}
+	sort.Ints(s.Idxs)
+
+	seen := map[string]bool{}
+	for _, idx := range s.Idxs {
+		name := tis[idx].Name
+		if name != "" && !seen[name] {
+			seen[name] = true
+			s.Names = append(s.Names, name)
+		}
+	}
+
+	return s
+}
+
+//------------------------------------------------------------
+// Helpers
+//------------------------------------------------------------
+
+// Checks if both lists contain same names in any order.
+func isEqualNames(a, b []string) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := map[string]bool{}
+	for _, name := range a {
+		set[name] = true
+	}
+	for _, name := range b {
+		if !set[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns sorted union of sorted indexes.
+func unionIdxs(a, b []int) (res []int) {
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			res = append(res, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+// Free slots common to several busy calendars.
+//
+//	Bounds:   [_______________________________]
+//	Busy A:       [__]          [____]
+//	Busy B:              [___]
+//	Slots:    [__]    [_]     [_]      [______]
+package intvl
+
+import "time"
+
+//------------------------------------------------------------
+// Slot query model
+//------------------------------------------------------------
+
+// SlotQuery describes free slots to find.
+type SlotQuery struct {
+	Bounds   TimeIntervals  // periods to search within, e.g. working hours
+	Len      time.Duration  // minimum length of slot
+	Align    time.Duration  // slots start at multiples of Align from midnight, 0 for any time
+	Buffer   time.Duration  // free time kept before and after each busy period
+	Location *time.Location // location of midnight and of slots, UTC if nil
+
+	// Minimum number of calendars free during slot.
+	// If not positive, all calendars must be free.
+	MinFree int
+}
+
+// NewSlotQuery creates query for slots of at least
+// length len within bounds, all calendars free.
+// Bounds keep their location, query location
+// is that of the first bound start.
+func NewSlotQuery(len time.Duration, bounds ...*TimeInterval) *SlotQuery {
+
+	q := &SlotQuery{
+		Bounds: NewTimeIntervals_KeepLocation(bounds...),
+		Len:    len,
+	}
+	for _, ti := range q.Bounds {
+		q.Location = ti.Ts.Location()
+		break
+	}
+
+	return q
+}
+
+// Returns query location or UTC if not set.
+func (q *SlotQuery) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+	return q.Location
+}
+
+//------------------------------------------------------------
+// Slot search
+//------------------------------------------------------------
+
+// FindSlots returns maximal free periods within query bounds
+// where enough calendars are free. Each slot starts at aligned
+// time and is at least query length long, so meeting fits
+// at any aligned start from slot Ts up to slot Te minus length.
+// Slots are aligned and returned in query location.
+func FindSlots(calendars []TimeIntervals, q *SlotQuery) (slots TimeIntervals) {
+
+	slots = TimeIntervals{}
+
+	n := len(calendars)
+	k := q.MinFree
+	if k <= 0 || k > n {
+		k = n
+	}
+
+	// Each calendar's busy time counted once,
+	// widened by buffer on both sides
+	all := TimeIntervals{}
+	for _, busy := range calendars {
+		set := NewIntervalSet(busy...).Expand(q.Buffer, q.Buffer)
+		all = append(all, set.tis...)
+	}
+
+	// Busy where more than n-k calendars are busy
+	busy := NewIntervalSet(all.Depth().AtLeast(n - k + 1)...)
+	free := NewIntervalSet(q.Bounds...).Subtract(busy)
+
+	loc := q.location()
+	for _, ti := range free.tis {
+
+		ts := alignUp(ti.Ts.In(loc), q.Align)
+		te := ti.Te.In(loc)
+		if te.Sub(ts) < q.Len || q.Len <= 0 && !ts.Before(te) {
+			continue
+		}
+
+		slots = append(slots, &TimeInterval{Ts: ts, Te: te, idx: len(slots)})
+	}
+
+	return
+}
+
+// Returns earliest time not before t that is
+// a multiple of align from midnight of t's day
+// in t's location.
+func alignUp(t time.Time, align time.Duration) time.Time {
+
+	if align <= 0 {
+		return t
+	}
+
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+
+	offset := t.Sub(midnight)
+	if rem := offset % align; rem != 0 {
+		offset += align - rem
+	}
+
+	return midnight.Add(offset)
+}
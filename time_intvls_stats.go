@@ -0,0 +1,191 @@
+// Coverage statistics of time intervals.
+//
+//	Source:    [ a ]  [  b  ]        [ c ]
+//	             [ d ]
+//	Span:      [___________________________]
+//	Holes:                  [      ]
+//	TotalLen:  sum of a,b,c,d counting overlaps once
+package intvl
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+//------------------------------------------------------------
+// Statistics model
+//------------------------------------------------------------
+
+// Stats summarizes coverage of time intervals.
+type Stats struct {
+	Count    int
+	Span     *TimeInterval // nil if there are no intervals
+	TotalLen time.Duration // covered time, overlaps counted once
+	Lens     LenStats      // lengths of intervals
+	Holes    LenStats      // lengths of holes within span
+}
+
+// LenStats summarizes distribution of lengths.
+type LenStats struct {
+	Count  int
+	Total  time.Duration
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	Median time.Duration
+
+	// Sorted lengths for percentiles
+	lens []time.Duration
+}
+
+// Creates length statistics.
+func newLenStats(lens []time.Duration) (s LenStats) {
+
+	sort.Slice(lens, func(i, j int) bool { return lens[i] < lens[j] })
+
+	s.lens = lens
+	s.Count = len(lens)
+	if s.Count == 0 {
+		return
+	}
+
+	for _, l := range lens {
+		s.Total += l
+	}
+
+	s.Min = lens[0]
+	s.Max = lens[len(lens)-1]
+	s.Mean = s.Total / time.Duration(s.Count)
+	s.Median = s.Percentile(50)
+	return
+}
+
+// Percentile returns length below which p percent of lengths fall,
+// interpolating linearly between closest ranks. P is clamped
+// to [0, 100], zero is returned if p is NaN or there are no lengths.
+func (s LenStats) Percentile(p float64) time.Duration {
+
+	if len(s.lens) == 0 || math.IsNaN(p) {
+		return 0
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(s.lens)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+
+	frac := rank - float64(lo)
+	return s.lens[lo] + time.Duration(frac*float64(s.lens[hi]-s.lens[lo]))
+}
+
+//------------------------------------------------------------
+// Time Intervals statistics
+//------------------------------------------------------------
+
+// Stats computes coverage statistics.
+func (tis TimeIntervals) Stats() (s Stats) {
+
+	s.Count = len(tis)
+	s.Span = tis.Span()
+
+	set := NewIntervalSet(tis...)
+	s.TotalLen = set.TotalLen()
+
+	lens := make([]time.Duration, len(tis))
+	for i, ti := range tis {
+		lens[i] = ti.Len()
+	}
+	s.Lens = newLenStats(lens)
+
+	holes := set.Holes()
+	lens = make([]time.Duration, len(holes))
+	for i, ti := range holes {
+		lens[i] = ti.Len()
+	}
+	s.Holes = newLenStats(lens)
+
+	return
+}
+
+// Span returns interval from earliest start to latest end
+// or nil if there are no intervals.
+func (tis TimeIntervals) Span() *TimeInterval {
+
+	if len(tis) == 0 {
+		return nil
+	}
+
+	span := &TimeInterval{Ts: tis[0].Ts, Te: tis[0].Te}
+	for _, ti := range tis[1:] {
+		span.Ts = earlierOf(span.Ts, ti.Ts)
+		span.Te = laterOf(span.Te, ti.Te)
+	}
+
+	return span
+}
+
+// TotalLen returns covered time counting overlaps once.
+func (tis TimeIntervals) TotalLen() time.Duration {
+	return NewIntervalSet(tis...).TotalLen()
+}
+
+// Holes returns uncovered intervals within span.
+func (tis TimeIntervals) Holes() TimeIntervals {
+	return NewIntervalSet(tis...).Holes()
+}
+
+// CoverageRatio returns part of bounds covered by intervals,
+// from 0 to 1. Returns 0 for empty bounds.
+func (tis TimeIntervals) CoverageRatio(bounds *TimeInterval) float64 {
+	return NewIntervalSet(tis...).CoverageRatio(bounds)
+}
+
+//------------------------------------------------------------
+// Interval Set statistics
+//------------------------------------------------------------
+
+// Span returns interval from first member start
+// to last member end or nil if set is empty.
+func (s IntervalSet) Span() *TimeInterval {
+
+	if len(s.tis) == 0 {
+		return nil
+	}
+	return &TimeInterval{Ts: s.tis[0].Ts, Te: s.tis[len(s.tis)-1].Te}
+}
+
+// TotalLen returns covered time.
+func (s IntervalSet) TotalLen() (total time.Duration) {
+
+	for _, ti := range s.tis {
+		total += ti.Len()
+	}
+	return
+}
+
+// Holes returns intervals between members.
+func (s IntervalSet) Holes() TimeIntervals {
+
+	holes := TimeIntervals{}
+	for i := 1; i < len(s.tis); i++ {
+		holes = append(holes, &TimeInterval{
+			Ts:  s.tis[i-1].Te,
+			Te:  s.tis[i].Ts,
+			idx: i - 1,
+		})
+	}
+	return holes
+}
+
+// CoverageRatio returns part of bounds covered by set,
+// from 0 to 1. Returns 0 for empty bounds.
+func (s IntervalSet) CoverageRatio(bounds *TimeInterval) float64 {
+
+	if bounds.Len() <= 0 {
+		return 0
+	}
+
+	covered := s.Intersect(NewIntervalSet(bounds)).TotalLen()
+	return float64(covered) / float64(bounds.Len())
+}
@@ -1,7 +1,10 @@
 package intvl
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -422,3 +425,1067 @@ func TestIntervals_Gaps(t *testing.T) {
 	fmt.Println(NewTimeIntervals(tisRes.Gaps()...))
 
 }
+
+// Tests iCalendar export and import.
+func TestIntervals_ICS(t *testing.T) {
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("No time zone database:", err)
+	}
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, berlin)
+
+	tis := TimeIntervals{
+		&TimeInterval{Name: "Outage; db, primary", Ts: t0, Te: t0.Add(2 * time.Hour)},
+		&TimeInterval{Name: "Free", Ts: t0.Add(4 * time.Hour).UTC(), Te: t0.Add(5 * time.Hour).UTC()},
+	}
+
+	var buf bytes.Buffer
+	if err := tis.WriteICS(&buf); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(buf.String())
+
+	res, err := ReadICS(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(res)
+
+	if len(res) != 2 || !res.IsEqual(tis) {
+		t.Error("ICS round trip failed")
+	}
+	if res[0].Name != tis[0].Name || res[0].Ts.Location().String() != "Europe/Berlin" {
+		t.Error("ICS round trip must preserve SUMMARY and TZID")
+	}
+
+	// UIDs don't depend on position of intervals
+	uids := func(tis TimeIntervals) (res []string) {
+		var buf bytes.Buffer
+		tis.WriteICS(&buf)
+		for _, line := range strings.Split(buf.String(), "\r\n") {
+			if strings.HasPrefix(line, "UID:") {
+				res = append(res, line)
+			}
+		}
+		return
+	}
+	before := uids(tis)
+	after := uids(TimeIntervals{tis[1].Shift(time.Hour), tis[0], tis[1], tis[1]})
+	if after[1] != before[0] || after[2] != before[1] || after[3] == after[2] {
+		t.Error("ICS UIDs must be stable and unique:", before, after)
+	}
+
+	// Recurrence
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20150320T090000\r\n" +
+		"DURATION:PT30M\r\n" +
+		"RRULE:FREQ=DAILY;INTERVAL=2;COUNT=6\r\n" +
+		"SUMMARY:Stand\r\n" +
+		" up\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	res, err = ReadICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(res)
+
+	if len(res) != 6 || res[0].Name != "Standup" {
+		t.Error("ICS recurrence expansion failed")
+	}
+
+	// Wall clock is kept across daylight saving change
+	if res[5].Ts.Hour() != 9 || res[5].Len() != 30*time.Minute {
+		t.Error("ICS recurrence must keep local time:", res[5].Ts)
+	}
+}
+
+// Tests CSV export and import.
+func TestIntervals_CSV(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	tis := NewTimeIntervals(
+		&TimeInterval{Name: "Backup, nightly", Ts: t0, Te: t0.Add(2 * time.Hour), Dt: 15 * time.Minute},
+		&TimeInterval{Name: "Upgrade", Ts: t0.Add(4 * time.Hour), Te: t0.Add(5 * time.Hour)},
+	)
+
+	var buf bytes.Buffer
+	if err := tis.WriteCSV(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(buf.String())
+
+	res, err := ReadCSV(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(res)
+
+	if !res.IsEqual(tis) || res[0].Name != "Backup, nightly" {
+		t.Error("CSV round trip failed")
+	}
+
+	// Custom columns, some rows invalid
+	format := &CSVFormat{
+		Name:     "Window",
+		Start:    "From",
+		Duration: "Length",
+		Layout:   "2006-01-02 15:04",
+		Location: time.UTC,
+		Comma:    ';',
+	}
+
+	csv := "Window;From;Length\n" +
+		"A;2015-03-15 10:00;2h\n" +
+		"B;2015-03-15 25:00;1h\n" +
+		"C;2015-03-16 10:00;\n" +
+		"D;2015-03-17 10:00;1d\n"
+
+	res, err = ReadCSV(strings.NewReader(csv), format)
+	fmt.Println(res)
+	fmt.Println(err)
+
+	if len(res) != 2 || res[1].Len() != 24*time.Hour {
+		t.Error("CSV must return valid rows")
+	}
+
+	errs, ok := err.(CSVErrors)
+	if !ok || len(errs) != 2 || errs[0].Line != 3 || errs[0].Column != "From" || errs[1].Line != 4 {
+		t.Error("CSV must report each invalid row with its line:", err)
+	}
+
+	// Partial format defaults to RFC3339 times
+	csv = "start,end\n2015-03-15T10:00:00Z,2015-03-15T12:00:00Z\n"
+	res, err = ReadCSV(strings.NewReader(csv), &CSVFormat{Start: "start", End: "end"})
+	if err != nil || len(res) != 1 || res[0].Len() != 2*time.Hour {
+		t.Error("CSV format without layout must read RFC3339:", err)
+	}
+}
+
+// Tests constructors that don't modify supplied intervals.
+func TestIntervals_Copy(t *testing.T) {
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("No time zone database:", err)
+	}
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, tokyo)
+	ti1 := &TimeInterval{Name: "One", Ts: t0.Add(2 * time.Hour), Te: t0.Add(3 * time.Hour)}
+	ti2 := &TimeInterval{Name: "Two", Ts: t0, Te: t0.Add(time.Hour), Times: []time.Time{t0}}
+
+	tis := NewTimeIntervals_Copy(ti1, ti2)
+	if tis[0].Name != "Two" || tis[0].Ts.Location() != time.UTC {
+		t.Error("NewTimeIntervals_Copy must sort and normalize copies")
+	}
+	if ti1.Ts.Location() != tokyo || ti2.Times[0].Location() != tokyo || tis[0] == ti2 {
+		t.Error("NewTimeIntervals_Copy must not modify supplied intervals")
+	}
+
+	tis = NewTimeIntervals_KeepLocation(ti1, ti2)
+	if tis[0].Ts.Location() != tokyo {
+		t.Error("NewTimeIntervals_KeepLocation must keep location")
+	}
+
+	// Deep clone
+	clone := tis.Clone()
+	clone[0].Ts = clone[0].Ts.Add(time.Minute)
+	clone[0].Times[0] = clone[0].Ts
+	if !tis[0].Ts.Equal(t0) || !tis[0].Times[0].Equal(t0) {
+		t.Error("Clone must be deep")
+	}
+
+	// Display zone
+	rt := NewRuntime()
+	rt.DisplayLocation = nil
+	if str := tis.String_Runtime(rt); !strings.Contains(str, "JST") {
+		t.Error("Runtime without display location must show own location:", str)
+	}
+	if str := tis.String(); strings.Contains(str, "JST") {
+		t.Error("Default runtime must show UTC:", str)
+	}
+}
+
+// Tests point queries.
+func TestIntervals_Query(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	a := &TimeInterval{Name: "a", Ts: h(0), Te: h(2)}
+	b := &TimeInterval{Name: "b", Ts: h(1), Te: h(8)}
+	c := &TimeInterval{Name: "c", Ts: h(3), Te: h(4)}
+	d := &TimeInterval{Name: "d", Ts: h(10), Te: h(12)}
+	tis := NewTimeIntervals(a, b, c, d)
+
+	if !a.Contains(h(0)) || a.Contains(h(2)) {
+		t.Error("Contains must follow [) boundary semantics")
+	}
+
+	tests := []struct {
+		t                       time.Time
+		at, next, prev, nearest *TimeInterval
+	}{
+		{h(-1), nil, a, nil, a},
+		{h(1), b, c, nil, b},
+		{h(3), c, d, a, c},
+		{h(8), nil, d, b, b},
+		{h(9).Add(30 * time.Minute), nil, d, b, d},
+		{h(12), nil, nil, d, d},
+	}
+
+	for _, test := range tests {
+		if tis.At(test.t) != test.at {
+			t.Error("At failed:", test.t)
+		}
+		if tis.Next(test.t) != test.next {
+			t.Error("Next failed:", test.t)
+		}
+		if tis.Prev(test.t) != test.prev {
+			t.Error("Prev failed:", test.t)
+		}
+		if tis.Nearest(test.t) != test.nearest {
+			t.Error("Nearest failed:", test.t)
+		}
+	}
+
+	// Indexed queries agree with scan of unindexed copy
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+	nested := NewTimeIntervals(
+		&TimeInterval{Ts: m(0), Te: m(100)},
+		&TimeInterval{Ts: m(5), Te: m(20)},
+		&TimeInterval{Ts: m(10), Te: m(15)},
+		&TimeInterval{Ts: m(10), Te: m(60)},
+		&TimeInterval{Ts: m(30), Te: m(40)},
+		&TimeInterval{Ts: m(40), Te: m(40)},
+		&TimeInterval{Ts: m(70), Te: m(120)},
+		&TimeInterval{Ts: m(110), Te: m(115)},
+	)
+	scanned := append(TimeIntervals{}, nested...)
+	if nested.index() == nil || scanned.index() != nil || nested[1:].index() != nil {
+		t.Error("Only intervals created by NewTimeIntervals must be indexed")
+	}
+	closed := NewRuntime()
+	closed.Boundary = BOUNDS_CLOSED
+	for _, rt := range []*Runtime{NewRuntime(), closed} {
+		for n := -5; n <= 125; n++ {
+			if nested.at(rt, m(n)) != scanned.at(rt, m(n)) {
+				t.Error("Indexed At differs from scan at minute", n, rt.Boundary)
+			}
+			if nested.prev(rt, m(n)) != scanned.prev(rt, m(n)) {
+				t.Error("Indexed Prev differs from scan at minute", n, rt.Boundary)
+			}
+		}
+	}
+
+	// Canonical set
+	s := NewIntervalSet(tis...)
+	if !s.Contains(h(7)) || s.Contains(h(8)) {
+		t.Error("IntervalSet Contains failed")
+	}
+	if p := s.Prev(h(10)); p == nil || !p.Te.Equal(h(8)) {
+		t.Error("IntervalSet Prev failed:", p)
+	}
+	if n := s.Nearest(h(9).Add(30 * time.Minute)); n == nil || !n.Ts.Equal(h(10)) {
+		t.Error("IntervalSet Nearest failed:", n)
+	}
+	if s.At(h(9)) != nil || s.Next(h(10)) != nil {
+		t.Error("IntervalSet At/Next failed")
+	}
+}
+
+// Tests transformations.
+func TestIntervals_Transform(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	a := &TimeInterval{Name: "a", Ts: m(0), Te: m(10), DtMode: DTMODE_DISCRETE, Times: []time.Time{m(1), m(9)}}
+	b := &TimeInterval{Name: "b", Ts: m(18), Te: m(20)}
+	c := &TimeInterval{Name: "c", Ts: m(40), Te: m(60)}
+	tis := NewTimeIntervals(a, b, c)
+
+	// Interval
+	if sh := a.Shift(5 * time.Minute); !sh.Ts.Equal(m(5)) || !sh.Times[1].Equal(m(14)) || !a.Ts.Equal(m(0)) {
+		t.Error("Shift failed:", sh)
+	}
+	if sh := a.Shrink(2*time.Minute, 0); len(sh.Times) != 1 || sh.Name != "a" {
+		t.Error("Shrink must drop moments outside of interval:", sh)
+	}
+	if a.Shrink(5*time.Minute, 5*time.Minute) != nil {
+		t.Error("Shrink to nothing must return nil")
+	}
+	if cl := c.Clamp(&TimeInterval{Ts: m(30), Te: m(50)}); !cl.Te.Equal(m(50)) || a.Clamp(c) != nil {
+		t.Error("Clamp failed:", cl)
+	}
+	if sc := b.Scale(2, m(10)); !sc.Ts.Equal(m(26)) || !sc.Te.Equal(m(30)) {
+		t.Error("Scale failed:", sc)
+	}
+
+	// Intervals
+	ex := tis.Expand(5*time.Minute, 5*time.Minute)
+	fmt.Println(ex)
+	if len(ex) != 2 || ex[0].Name != "a,b" || !ex[0].Te.Equal(m(25)) || !ex[0].Ts.Equal(m(-5)) {
+		t.Error("Expand must merge intervals that start to overlap:", ex)
+	}
+
+	sh := tis.Shrink(time.Minute, time.Minute)
+	if len(sh) != 2 || sh[1].Name != "c" {
+		t.Error("Shrink must drop intervals that shrink to nothing:", sh)
+	}
+
+	cl := tis.Clamp(&TimeInterval{Ts: m(5), Te: m(45)})
+	if len(cl) != 3 || !cl[0].Ts.Equal(m(5)) || !cl[2].Te.Equal(m(45)) {
+		t.Error("Clamp failed:", cl)
+	}
+
+	// Touching intervals aren't merged
+	if mg := NewTimeIntervals(a.Clone(), a.Shift(10*time.Minute)).Merge(); len(mg) != 2 {
+		t.Error("Merge must keep touching intervals apart:", mg)
+	}
+
+	// Set
+	s := NewIntervalSet(tis...).Expand(0, 20*time.Minute)
+	if s.Len() != 1 {
+		t.Error("IntervalSet Expand failed:", s)
+	}
+}
+
+// Tests coverage statistics.
+func TestIntervals_Stats(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	tis := NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(2)},
+		&TimeInterval{Ts: h(1), Te: h(4)},
+		&TimeInterval{Ts: h(6), Te: h(7)},
+		&TimeInterval{Ts: h(10), Te: h(14)},
+	)
+
+	s := tis.Stats()
+	fmt.Printf("%+v\n", s)
+
+	if !s.Span.IsEqual_TsTe(&TimeInterval{Ts: h(0), Te: h(14)}) {
+		t.Error("Span failed:", s.Span)
+	}
+	if s.TotalLen != 9*time.Hour {
+		t.Error("TotalLen must count overlaps once:", s.TotalLen)
+	}
+	if s.Holes.Count != 2 || s.Holes.Max != 3*time.Hour || s.Holes.Min != 2*time.Hour {
+		t.Error("Holes failed:", s.Holes)
+	}
+	if s.Lens.Min != time.Hour || s.Lens.Max != 4*time.Hour || s.Lens.Mean != 150*time.Minute || s.Lens.Median != 150*time.Minute {
+		t.Error("Lengths failed:", s.Lens)
+	}
+	if p := s.Lens.Percentile(100); p != 4*time.Hour {
+		t.Error("Percentile failed:", p)
+	}
+	if p := s.Lens.Percentile(math.NaN()); p != 0 {
+		t.Error("Percentile of NaN must be zero:", p)
+	}
+	if p := s.Lens.Percentile(math.Inf(1)); p != 4*time.Hour {
+		t.Error("Percentile must clamp infinity:", p)
+	}
+
+	if r := tis.CoverageRatio(&TimeInterval{Ts: h(-2), Te: h(8)}); r != 0.5 {
+		t.Error("CoverageRatio failed:", r)
+	}
+
+	// No intervals
+	if s := (TimeIntervals{}).Stats(); s.Span != nil || s.TotalLen != 0 || s.Lens.Percentile(50) != 0 {
+		t.Error("Stats of no intervals failed")
+	}
+}
+
+// Tests depth profile.
+func TestIntervals_Depth(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	tis := NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(4)},
+		&TimeInterval{Ts: h(1), Te: h(3)},
+		&TimeInterval{Ts: h(2), Te: h(3)},
+		&TimeInterval{Ts: h(3), Te: h(5)},
+		&TimeInterval{Ts: h(7), Te: h(8)},
+		&TimeInterval{Ts: h(9), Te: h(9)},
+	)
+
+	p := tis.Depth()
+	for _, s := range p {
+		fmt.Println(s.Ts.Format(TIME_LAYOUT_DEBUG), "-", s.Te.Format(TIME_LAYOUT_DEBUG), s.Depth)
+	}
+
+	depths := []int{1, 2, 3, 2, 1, 0, 1}
+	if len(p) != len(depths) {
+		t.Fatal("Depth profile has wrong number of steps:", len(p))
+	}
+	for i, s := range p {
+		if s.Depth != depths[i] {
+			t.Error("Wrong depth at step", i, s.Depth)
+		}
+	}
+
+	if p.Max() != 3 || p.At(h(2)) != 3 || p.At(h(3)) != 2 || p.At(h(6)) != 0 {
+		t.Error("Depth queries failed")
+	}
+
+	at := p.TimeAtDepth()
+	if at[1] != 3*time.Hour || at[2] != 2*time.Hour || at[0] != 2*time.Hour {
+		t.Error("TimeAtDepth failed:", at)
+	}
+
+	if res := p.AtLeast(2); len(res) != 1 || !res[0].IsEqual_TsTe(&TimeInterval{Ts: h(1), Te: h(4)}) {
+		t.Error("AtLeast failed:", res)
+	}
+	if res := p.AtLeast(1); len(res) != 2 {
+		t.Error("AtLeast failed:", res)
+	}
+}
+
+// Tests load profile.
+func TestIntervals_Load(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	ti, _ := NewTimeInterval(h(0), h(4), WithDt(time.Hour), WithWeight(2))
+	tis := NewTimeIntervals(
+		ti,
+		&TimeInterval{Ts: h(2), Te: h(6), Weight: 1.5},
+		&TimeInterval{Ts: h(4), Te: h(6), Weight: 0.5},
+		&TimeInterval{Ts: h(8), Te: h(9), Weight: 0.1},
+		&TimeInterval{Ts: h(8), Te: h(9), Weight: 0.2},
+	)
+
+	p := tis.Load()
+	for _, s := range p {
+		fmt.Println(s.Ts.Format(TIME_LAYOUT_DEBUG), "-", s.Te.Format(TIME_LAYOUT_DEBUG), s.Load)
+	}
+
+	if len(p) != 5 || p[3].Load != 0 || !p[3].Ts.Equal(h(6)) {
+		t.Error("Load profile failed")
+	}
+	if peak := p.Peak(); peak.Load != 3.5 || !peak.Ts.Equal(h(2)) || !peak.Te.Equal(h(4)) {
+		t.Error("Peak failed:", peak)
+	}
+	if l := p.At(h(5)); l != 2 {
+		t.Error("At failed:", l)
+	}
+	if sum := p.Integral(time.Hour); math.Abs(sum-15.3) > 1e-9 {
+		t.Error("Integral failed:", sum)
+	}
+
+	if over := p.OverCapacity(Capacity(2), 0); len(over) != 1 || !over[0].IsEqual_TsTe(&TimeInterval{Ts: h(2), Te: h(4)}) {
+		t.Error("OverCapacity failed:", over)
+	}
+
+	// Capacity drops after 5h
+	capacity := func(t time.Time) float64 {
+		if t.Before(h(5)) {
+			return 4
+		}
+		return 1
+	}
+	if over := p.OverCapacity(capacity, 30*time.Minute); len(over) != 1 || !over[0].IsEqual_TsTe(&TimeInterval{Ts: h(5), Te: h(6)}) {
+		t.Error("OverCapacity with changing capacity failed:", over)
+	}
+
+	// Trailing zero weight interval extends profile
+	p = NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(1), Weight: 1},
+		&TimeInterval{Ts: h(1), Te: h(3)},
+	).Load()
+	if len(p) != 2 || p[1].Load != 0 || !p[1].Te.Equal(h(3)) {
+		t.Error("Load profile must reach latest end:", p)
+	}
+}
+
+// Tests segmentation.
+func TestIntervals_Segments(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	tis := NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(4), Name: "shift"},
+		&TimeInterval{Ts: h(2), Te: h(6), Name: "promo"},
+		&TimeInterval{Ts: h(4), Te: h(5), Name: "shift"},
+		&TimeInterval{Ts: h(8), Te: h(9), Name: "promo"},
+	)
+
+	segs := tis.Segments()
+	fmt.Println(segs.TimeIntervals())
+
+	names := []string{"shift", "shift,promo", "promo,shift", "promo", "promo"}
+	if len(segs) != len(names) {
+		t.Fatal("Wrong number of segments:", len(segs))
+	}
+	for i, s := range segs {
+		if s.Interval().Name != names[i] {
+			t.Error("Wrong names of segment", i, s.Names)
+		}
+	}
+	if len(segs[2].Idxs) != 2 || segs[2].Idxs[0] != 1 || segs[2].Idxs[1] != 2 {
+		t.Error("Wrong indexes of segment:", segs[2].Idxs)
+	}
+
+	// Equal label sets merged, gap keeps segments apart
+	merged := tis.Segments_MergeEqual()
+	fmt.Println(merged.TimeIntervals())
+
+	if len(merged) != 4 || !merged[1].Ts.Equal(h(2)) || !merged[1].Te.Equal(h(5)) || len(merged[1].Idxs) != 3 {
+		t.Error("Segments_MergeEqual failed:", merged)
+	}
+
+	// Zero-length interval doesn't split segment
+	tis = NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(4), Name: "a"},
+		&TimeInterval{Ts: h(2), Te: h(2), Name: "z"},
+	)
+	if segs := tis.Segments(); len(segs) != 1 || !segs[0].Te.Equal(h(4)) || len(segs[0].Idxs) != 1 {
+		t.Error("Zero-length interval must not split segment:", segs)
+	}
+}
+
+// Tests free slot search.
+func TestFindSlots(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 16, 0, 0, 0, 0, time.UTC)
+	at := func(hh, mm int) time.Time { return t0.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute) }
+
+	alice := NewTimeIntervals(
+		&TimeInterval{Ts: at(9, 0), Te: at(10, 0)},
+		&TimeInterval{Ts: at(13, 0), Te: at(14, 10)},
+	)
+	bob := NewTimeIntervals(
+		&TimeInterval{Ts: at(10, 30), Te: at(12, 0)},
+		&TimeInterval{Ts: at(15, 0), Te: at(16, 0)},
+	)
+	carol := NewTimeIntervals(
+		&TimeInterval{Ts: at(8, 0), Te: at(18, 0)},
+	)
+
+	q := NewSlotQuery(30*time.Minute, &TimeInterval{Ts: at(9, 0), Te: at(17, 0)})
+
+	slots := FindSlots([]TimeIntervals{alice, bob}, q)
+	fmt.Println(slots)
+
+	expect := TimeIntervals{
+		&TimeInterval{Ts: at(10, 0), Te: at(10, 30)},
+		&TimeInterval{Ts: at(12, 0), Te: at(13, 0)},
+		&TimeInterval{Ts: at(14, 10), Te: at(15, 0)},
+		&TimeInterval{Ts: at(16, 0), Te: at(17, 0)},
+	}
+	if len(slots) != len(expect) {
+		t.Fatal("Wrong number of slots:", len(slots))
+	}
+	for i, ti := range slots {
+		if !ti.IsEqual_TsTe(expect[i]) {
+			t.Error("Wrong slot", i, ti)
+		}
+	}
+
+	// Alignment and buffer
+	q.Align = 30 * time.Minute
+	q.Buffer = 10 * time.Minute
+	slots = FindSlots([]TimeIntervals{alice, bob}, q)
+	fmt.Println(slots)
+
+	if len(slots) != 1 || !slots[0].IsEqual_TsTe(&TimeInterval{Ts: at(16, 30), Te: at(17, 0)}) {
+		t.Error("Aligned slots with buffer failed:", slots)
+	}
+
+	// Buffer alone keeps short gaps
+	q.Align = 0
+	if slots := FindSlots([]TimeIntervals{alice, bob}, q); len(slots) != 3 || !slots[0].Ts.Equal(at(12, 10)) {
+		t.Error("Slots with buffer failed:", slots)
+	}
+
+	// At least 2 of 3 free
+	q = NewSlotQuery(time.Hour, &TimeInterval{Ts: at(9, 0), Te: at(17, 0)})
+	q.MinFree = 2
+	slots = FindSlots([]TimeIntervals{alice, bob, carol}, q)
+	fmt.Println(slots)
+
+	if len(slots) != 2 || !slots[0].IsEqual_TsTe(&TimeInterval{Ts: at(12, 0), Te: at(13, 0)}) {
+		t.Error("Slots with 2 of 3 free failed:", slots)
+	}
+
+	// Nobody needs to be free with zero calendars
+	if slots := FindSlots(nil, q); len(slots) != 1 {
+		t.Error("Slots without calendars must cover bounds:", slots)
+	}
+
+	// Alignment in query location with half hour offset,
+	// busy time given in UTC
+	ist := time.FixedZone("IST", 5*3600+1800)
+	local := func(hh, mm int) time.Time { return time.Date(2015, time.March, 16, hh, mm, 0, 0, ist) }
+	busy := NewTimeIntervals(&TimeInterval{Ts: local(10, 10), Te: local(11, 40)})
+
+	q = NewSlotQuery(30*time.Minute, &TimeInterval{Ts: local(9, 0), Te: local(17, 0)})
+	q.Align = time.Hour
+	slots = FindSlots([]TimeIntervals{busy}, q)
+	fmt.Println(slots)
+
+	if len(slots) != 2 || !slots[0].Ts.Equal(local(9, 0)) || !slots[1].Ts.Equal(local(12, 0)) ||
+		slots[1].Ts.Location() != ist || q.Bounds[0].Ts.Location() != ist {
+		t.Error("Slots must be aligned in query location:", slots)
+	}
+}
+
+// Tests partitioning onto lanes.
+func TestIntervals_Partition(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	tis := TimeIntervals{
+		&TimeInterval{Ts: h(3), Te: h(5), Name: "c"},
+		&TimeInterval{Ts: h(0), Te: h(2), Name: "a"},
+		&TimeInterval{Ts: h(1), Te: h(3), Name: "b"},
+		&TimeInterval{Ts: h(2), Te: h(6), Name: "d"},
+		&TimeInterval{Ts: h(1), Te: h(4), Name: "e"},
+	}
+
+	p := tis.Partition()
+	for i, lane := range p.Lanes {
+		fmt.Println("Lane", i)
+		fmt.Println(lane)
+	}
+
+	if len(p.Lanes) != 3 || len(p.Unplaced) != 0 {
+		t.Fatal("Partition must use minimum number of lanes:", len(p.Lanes))
+	}
+	for _, lane := range p.Lanes {
+		for i := 1; i < len(lane); i++ {
+			if lane[i].Ts.Before(lane[i-1].Te) {
+				t.Error("Lane must not contain overlaps:", lane)
+			}
+		}
+	}
+	if p.Lane[1] != 0 || p.Lane[3] != 0 || p.Lanes[p.Lane[0]][1] != tis[0] {
+		t.Error("Wrong lane assignments:", p.Lane)
+	}
+
+	// Fixed number of lanes
+	p = tis.Partition_Lanes(2)
+	if len(p.Lanes) != 2 || len(p.Unplaced) != 1 || p.Unplaced[0].Name != "e" || p.Lane[4] != -1 {
+		t.Error("Partition_Lanes must report unplaced intervals:", p.Unplaced)
+	}
+
+	// Touching intervals share lane only with closed-open bounds
+	rt := NewRuntime()
+	rt.Boundary = BOUNDS_CLOSED
+	if p := tis.Partition_Runtime(rt); len(p.Lanes) != 4 {
+		t.Error("Closed bounds need more lanes:", len(p.Lanes))
+	}
+}
+
+// Tests interval scheduling.
+func TestIntervals_Schedule(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	tis := TimeIntervals{
+		&TimeInterval{Ts: h(0), Te: h(4), Weight: 5, Name: "a"},
+		&TimeInterval{Ts: h(1), Te: h(2), Weight: 2, Name: "b"},
+		&TimeInterval{Ts: h(2), Te: h(3), Weight: 2, Name: "c"},
+		&TimeInterval{Ts: h(3), Te: h(4), Weight: 2, Name: "d"},
+		&TimeInterval{Ts: h(4), Te: h(6), Weight: 1, Name: "e"},
+		&TimeInterval{Ts: h(5), Te: h(7), Weight: 3, Name: "f"},
+	}
+	byWeight := func(ti *TimeInterval) float64 { return ti.Weight }
+
+	chosen, total := tis.Schedule(byWeight)
+	fmt.Println(chosen, total)
+
+	if total != 9 || len(chosen) != 4 || chosen[0].Name != "b" || chosen[3].Name != "f" {
+		t.Error("Schedule must choose subset of maximum weight:", total)
+	}
+
+	// Weight by length
+	chosen, total = tis.Schedule(func(ti *TimeInterval) float64 { return ti.Len().Hours() })
+	if total != 6 || chosen[0].Name != "a" {
+		t.Error("Schedule by length failed:", total)
+	}
+
+	// Brute force over all subsets
+	for mask := 0; mask < 1<<uint(len(tis)); mask++ {
+		sum := 0.0
+		var subset TimeIntervals
+		for i, ti := range tis {
+			if mask&(1<<uint(i)) != 0 {
+				subset = append(subset, ti)
+				sum += ti.Weight
+			}
+		}
+		_, _, overs := NewTimeIntervals_Copy(subset...).AnalyzeOverlaps()
+		if len(overs) == 0 && sum > 9 {
+			t.Error("Schedule missed better subset:", sum)
+		}
+	}
+
+	if max := tis.Schedule_MaxCount(); len(max) != 4 || max[0].Name != "b" {
+		t.Error("Schedule_MaxCount failed:", max)
+	}
+}
+
+// Tests overlap graph, components and maximum clique.
+func TestIntervals_Graph(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	tis := TimeIntervals{
+		&TimeInterval{Ts: m(0), Te: m(10), Name: "a"},
+		&TimeInterval{Ts: m(5), Te: m(30), Name: "b"},
+		&TimeInterval{Ts: m(20), Te: m(25), Name: "c"},
+		&TimeInterval{Ts: m(8), Te: m(22), Name: "d"},
+		&TimeInterval{Ts: m(30), Te: m(40), Name: "e"},
+		&TimeInterval{Ts: m(45), Te: m(50), Name: "f"},
+	}
+
+	g := tis.OverlapGraph()
+	fmt.Println(g.Edges)
+	if len(g.Edges[0]) != 2 || len(g.Edges[4]) != 0 || len(g.Edges[1]) != 3 {
+		t.Error("Wrong overlap graph:", g.Edges)
+	}
+
+	comps := tis.Components()
+	if len(comps) != 3 || len(comps[0].Idxs) != 4 || !comps[0].Span.IsEqual_TsTe(&TimeInterval{Ts: m(0), Te: m(30)}) {
+		t.Error("Wrong components:", comps)
+	}
+
+	clique := tis.MaxClique()
+	fmt.Println(clique)
+	if len(clique) != 3 || clique[0].Name != "a" || clique[2].Name != "d" {
+		t.Error("Wrong maximum clique:", clique)
+	}
+
+	// Tolerance joins nearby intervals
+	rt := NewRuntime()
+	rt.Tolerance = 5 * time.Minute
+	comps = tis.Components_Runtime(rt)
+	if len(comps) != 1 || comps[0].Intervals[5].Name != "f" {
+		t.Error("Tolerance must join components:", len(comps))
+	}
+	if clique := tis.MaxClique_Runtime(rt); len(clique) != 3 {
+		t.Error("Wrong maximum clique with tolerance:", clique)
+	}
+
+	// Touching intervals are adjacent with closed bounds
+	rt = NewRuntime()
+	rt.Boundary = BOUNDS_CLOSED
+	if comps := tis.Components_Runtime(rt); len(comps) != 2 {
+		t.Error("Closed bounds must join touching intervals:", len(comps))
+	}
+
+	// Zero-length interval doesn't stay active
+	tis = TimeIntervals{
+		&TimeInterval{Ts: m(0), Te: m(0), Name: "z"},
+		&TimeInterval{Ts: m(60), Te: m(120), Name: "a"},
+		&TimeInterval{Ts: m(180), Te: m(240), Name: "b"},
+	}
+	if clique := tis.MaxClique(); len(clique) != 1 || clique[0].Name != "a" {
+		t.Error("Zero-length interval must not join clique:", clique)
+	}
+
+	// Zero-length interval inside another is adjacent to none
+	tis = TimeIntervals{
+		&TimeInterval{Ts: m(0), Te: m(60), Name: "a"},
+		&TimeInterval{Ts: m(30), Te: m(30), Name: "z"},
+	}
+	if g := tis.OverlapGraph(); len(g.Edges[0]) != 0 || len(g.Edges[1]) != 0 {
+		t.Error("Zero-length interval must not have edges:", g.Edges)
+	}
+	if comps := tis.Components(); len(comps) != 2 {
+		t.Error("Zero-length interval must form own component:", len(comps))
+	}
+	if clique := tis.MaxClique(); len(clique) != 1 || clique[0].Name != "a" {
+		t.Error("Zero-length interval must not join clique:", clique)
+	}
+}
+
+// Tests temporal join.
+func TestIntervals_Join(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	deploys := NewTimeIntervals(
+		&TimeInterval{Ts: m(0), Te: m(10), Name: "d1"},
+		&TimeInterval{Ts: m(30), Te: m(40), Name: "d2"},
+		&TimeInterval{Ts: m(60), Te: m(65), Name: "d3"},
+		&TimeInterval{Ts: m(90), Te: m(95), Name: "d4"},
+	)
+	incidents := NewTimeIntervals(
+		&TimeInterval{Ts: m(5), Te: m(35), Name: "i1"},
+		&TimeInterval{Ts: m(32), Te: m(38), Name: "i2"},
+		&TimeInterval{Ts: m(70), Te: m(80), Name: "i3"},
+	)
+
+	pairs := deploys.Join(incidents, NewJoinQuery(JOIN_INNER))
+	for _, p := range pairs {
+		fmt.Println(p.Left.Name, p.Right.Name, p.Overlap, p.Relation)
+	}
+
+	if len(pairs) != 3 {
+		t.Fatal("Wrong number of inner pairs:", len(pairs))
+	}
+	if pairs[0].Relation != RELATION_OVERLAPS || pairs[0].Overlap != 5*time.Minute {
+		t.Error("Wrong first pair:", pairs[0])
+	}
+	if pairs[1].Relation != RELATION_OVERLAPPED_BY || pairs[2].Relation != RELATION_CONTAINS || pairs[2].RightIdx != 1 {
+		t.Error("Wrong pairs of d2:", pairs[1], pairs[2])
+	}
+
+	// Left keeps unmatched, anti keeps only unmatched
+	if pairs := deploys.Join(incidents, NewJoinQuery(JOIN_LEFT)); len(pairs) != 5 || pairs[4].Right != nil || pairs[4].RightIdx != -1 {
+		t.Error("Wrong left join:", pairs)
+	}
+	if pairs := deploys.Join(incidents, NewJoinQuery(JOIN_ANTI)); len(pairs) != 2 || pairs[0].Left.Name != "d3" {
+		t.Error("Wrong anti join:", pairs)
+	}
+
+	// Incidents starting within 10 minutes after deploy
+	q := NewJoinQuery(JOIN_INNER)
+	q.Lookahead = 10 * time.Minute
+	pairs = deploys.Join(incidents, q)
+	if len(pairs) != 4 || pairs[3].Left.Name != "d3" || pairs[3].Overlap != 0 || pairs[3].Relation != RELATION_BEFORE {
+		t.Error("Wrong join with lookahead:", len(pairs))
+	}
+
+	// Compare against nested loops
+	rt := defaultRuntime()
+	for _, lookback := range []time.Duration{0, 5 * time.Minute, time.Hour} {
+		q := NewJoinQuery(JOIN_INNER)
+		q.Lookback = lookback
+		count := 0
+		for _, d := range deploys {
+			for _, i := range incidents {
+				if rt.IsOverlap(&TimeInterval{Ts: d.Ts.Add(-lookback), Te: d.Te}, i) {
+					count++
+				}
+			}
+		}
+		if pairs := deploys.Join(incidents, q); len(pairs) != count {
+			t.Error("Join with lookback", lookback, "found", len(pairs), "pairs, expected", count)
+		}
+	}
+
+	// Unknown mode, including zero value query, panics
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Join with unknown mode must panic")
+			}
+		}()
+		deploys.Join(incidents, &JoinQuery{})
+	}()
+}
+
+// Tests batch assignment of points.
+func TestIntervals_Assign(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	m := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	tis := NewTimeIntervals(
+		&TimeInterval{Ts: m(0), Te: m(10), Name: "a"},
+		&TimeInterval{Ts: m(5), Te: m(50), Name: "b"},
+		&TimeInterval{Ts: m(20), Te: m(30), Name: "c"},
+		&TimeInterval{Ts: m(60), Te: m(70), Name: "d"},
+	)
+
+	times := []time.Time{m(-5), m(3), m(10), m(25), m(30), m(52), m(58), m(70), m(80)}
+
+	asof := tis.Assign(times, ASSIGN_ASOF)
+	fmt.Println(asof)
+
+	expect := []int{-1, 0, 1, 2, 1, 1, 1, 3, 3}
+	for k, i := range asof {
+		if i != expect[k] {
+			t.Error("Wrong as-of assignment of point", k, i)
+		}
+	}
+
+	// Compare against single point queries
+	for _, mode := range []string{ASSIGN_COVERING, ASSIGN_ASOF, ASSIGN_NEAREST} {
+		for k, i := range tis.Assign(times, mode) {
+
+			var ti *TimeInterval
+			switch mode {
+			case ASSIGN_COVERING:
+				ti = tis.At(times[k])
+			case ASSIGN_ASOF:
+				if ti = tis.At(times[k]); ti == nil {
+					ti = tis.Prev(times[k])
+				}
+			case ASSIGN_NEAREST:
+				ti = tis.Nearest(times[k])
+			}
+
+			if i == -1 && ti != nil || i != -1 && tis[i] != ti {
+				t.Error(mode, "assignment of point", k, "differs from point query")
+			}
+		}
+	}
+
+	// Closed bounds cover touching ends
+	rt := NewRuntime()
+	rt.Boundary = BOUNDS_CLOSED
+	for k, i := range tis.Assign_Runtime(rt, times, ASSIGN_COVERING) {
+		if i != tis.at(rt, times[k]) {
+			t.Error("Closed covering assignment of point", k, "differs from point query:", i)
+		}
+	}
+
+	// Unknown mode panics
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Assign with unknown mode must panic")
+			}
+		}()
+		tis.Assign(times, "")
+	}()
+}
+
+// Tests diff of schedule versions.
+func TestDiff(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	before := NewTimeIntervals(
+		&TimeInterval{Ts: h(0), Te: h(2), Name: "a"},
+		&TimeInterval{Ts: h(3), Te: h(6), Name: "b"},
+		&TimeInterval{Ts: h(7), Te: h(8), Name: "c"},
+		&TimeInterval{Ts: h(9), Te: h(10), Name: "d"},
+	)
+	after := NewTimeIntervals(
+		&TimeInterval{Ts: h(1), Te: h(3), Name: "a"},
+		&TimeInterval{Ts: h(3), Te: h(5), Name: "b"},
+		&TimeInterval{Ts: h(7), Te: h(8), Name: "c"},
+		&TimeInterval{Ts: h(11), Te: h(12), Name: "e"},
+	)
+
+	d := Diff(before, after)
+	fmt.Println(d)
+
+	kinds := map[string]string{}
+	for _, ch := range d.Changes {
+		name := ""
+		if ch.Old != nil {
+			name = ch.Old.Name
+		} else {
+			name = ch.New.Name
+		}
+		kinds[name] = strings.Join(ch.Kinds, ",")
+	}
+
+	if len(d.Changes) != 4 || kinds["a"] != DIFF_MOVED || kinds["b"] != DIFF_SHORTENED ||
+		kinds["d"] != DIFF_REMOVED || kinds["e"] != DIFF_ADDED {
+		t.Error("Wrong changes:", kinds)
+	}
+	if d.Added.TotalLen() != 2*time.Hour || d.Removed.TotalLen() != 3*time.Hour {
+		t.Error("Wrong coverage changes:", d.Added, d.Removed)
+	}
+
+	// Matching by key reports renames
+	byStart := func(ti *TimeInterval) string { return ti.Ts.String() }
+	after[2].Name = "c2"
+	d = Diff_Key(before, after, byStart)
+	if len(d.Changes) != 6 || d.Changes[2].Kinds[0] != DIFF_RENAMED {
+		t.Error("Diff_Key must report renamed interval:", d.Changes[2].Kinds)
+	}
+
+	// Moved and resized interval reports both
+	d = Diff(
+		TimeIntervals{&TimeInterval{Ts: h(0), Te: h(2), Name: "a"}},
+		TimeIntervals{&TimeInterval{Ts: h(5), Te: h(8), Name: "a"}},
+	)
+	if len(d.Changes) != 1 || strings.Join(d.Changes[0].Kinds, ",") != DIFF_MOVED+","+DIFF_EXTENDED {
+		t.Error("Moved and extended interval must report both:", d.Changes)
+	}
+
+	if d := Diff(before, before); !d.IsEmpty() {
+		t.Error("Diff of same versions must be empty")
+	}
+}
+
+// Tests coverage and tolerant equality.
+func TestIntervals_Equality(t *testing.T) {
+
+	t0 := time.Date(2015, time.March, 15, 12, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Hour) }
+
+	whole := TimeIntervals{&TimeInterval{Ts: h(0), Te: h(2), Name: "a"}}
+	parts := TimeIntervals{
+		&TimeInterval{Ts: h(1), Te: h(2), Name: "b"},
+		&TimeInterval{Ts: h(0), Te: h(1), Name: "a"},
+	}
+
+	if whole.IsEqual(parts) || !whole.IsEqual_Coverage(parts) {
+		t.Error("IsEqual_Coverage must ignore fragmentation")
+	}
+
+	skewed := TimeIntervals{&TimeInterval{Ts: h(0).Add(-time.Second), Te: h(2).Add(2 * time.Second), Name: "a"}}
+	if whole.IsEqual_Tolerance(skewed, time.Second) || !whole.IsEqual_Tolerance(skewed, 2*time.Second) {
+		t.Error("IsEqual_Tolerance failed")
+	}
+
+	// Comparator fields
+	c := NewComparator()
+	c.Tolerance = 2 * time.Second
+	if !c.IsEqual_TimeIntervals(whole, skewed) || !c.IsEqual_Coverage(whole, parts) {
+		t.Error("Comparator with tolerance failed")
+	}
+
+	// Gaps within tolerance are covered
+	c.Tolerance = time.Second
+	gapped := TimeIntervals{
+		&TimeInterval{Ts: h(0), Te: h(1), Name: "a"},
+		&TimeInterval{Ts: h(1).Add(time.Second), Te: h(2), Name: "a"},
+	}
+	if !c.IsEqual_Coverage(whole, gapped) || !c.IsEqual_Coverage(gapped, whole) {
+		t.Error("IsEqual_Coverage must bridge gaps within tolerance")
+	}
+	c.Tolerance = 0
+	if c.IsEqual_Coverage(whole, gapped) {
+		t.Error("IsEqual_Coverage must not bridge gaps beyond tolerance")
+	}
+	c.Tolerance = 2 * time.Second
+
+	renamed := whole[0].Clone()
+	renamed.Name = "b"
+	renamed.Times = []time.Time{h(1)}
+	if !c.IsEqual(whole[0], renamed) {
+		t.Error("Comparator must ignore Name and Times by default")
+	}
+
+	c.Name = true
+	if c.IsEqual(whole[0], renamed) {
+		t.Error("Comparator must compare Name")
+	}
+
+	c.Name = false
+	c.Times = true
+	if c.IsEqual(whole[0], renamed) {
+		t.Error("Comparator must compare Times")
+	}
+	whole[0].Times = []time.Time{h(1).Add(time.Second)}
+	if !c.IsEqual(whole[0], renamed) {
+		t.Error("Comparator must compare Times within tolerance")
+	}
+}
@@ -0,0 +1,182 @@
+// Transformations of time intervals.
+// Each transformation returns new intervals, receiver is not modified.
+package intvl
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+// Time Intervals transformations
+//------------------------------------------------------------
+
+// Shift moves each interval by d.
+func (tis TimeIntervals) Shift(d time.Duration) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range tis {
+		res = append(res, ti.Shift(d))
+	}
+
+	return
+}
+
+// Expand pads each interval by left and right durations.
+// Intervals that overlap after expansion are merged, see Merge.
+func (tis TimeIntervals) Expand(left, right time.Duration) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range tis {
+		if ex := ti.Expand(left, right); ex != nil {
+			res = append(res, ex)
+		}
+	}
+
+	return res.Merge()
+}
+
+// Shrink cuts left and right durations off each interval.
+// Intervals that shrink to nothing are dropped.
+func (tis TimeIntervals) Shrink(left, right time.Duration) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range tis {
+		if sh := ti.Shrink(left, right); sh != nil {
+			res = append(res, sh)
+		}
+	}
+
+	return
+}
+
+// Clamp limits each interval to bounds.
+// Intervals outside of bounds are dropped.
+func (tis TimeIntervals) Clamp(bounds *TimeInterval) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range tis {
+		if cl := ti.Clamp(bounds); cl != nil {
+			res = append(res, cl)
+		}
+	}
+
+	return
+}
+
+// Scale stretches each interval by factor relative to anchor.
+// Returns empty intervals if factor is not positive.
+func (tis TimeIntervals) Scale(factor float64, anchor time.Time) (res TimeIntervals) {
+
+	res = TimeIntervals{}
+	for _, ti := range tis {
+		if sc := ti.Scale(factor, anchor); sc != nil {
+			res = append(res, sc)
+		}
+	}
+
+	return
+}
+
+// Merge combines overlapping intervals according to default
+// runtime boundary semantics and returns them sorted by Ts.
+// Merged interval takes Dt and DtMode of the earliest one,
+// its Name lists distinct names, comma-separated,
+// its Times are union of all Times and its Data
+// is first non-nil payload, see PayloadPolicy.
+//
+//	Source:    [ a ]  [  b  ]
+//	             [ c ]         [ d ]
+//	Result:    [   a,c  ]  [ d ]
+func (tis TimeIntervals) Merge() (res TimeIntervals) {
+	return tis.Merge_Runtime(defaultRuntime())
+}
+
+// Merge_Runtime combines overlapping intervals according
+// to runtime boundary semantics.
+func (tis TimeIntervals) Merge_Runtime(rt *Runtime) (res TimeIntervals) {
+	return tis.merge(rt, nil)
+}
+
+// Merges intervals using runtime and payload policy.
+func (tis TimeIntervals) merge(rt *Runtime, p *PayloadPolicy) (res TimeIntervals) {
+
+	sorted := NewTimeIntervals_KeepLocation(tis...)
+
+	res = TimeIntervals{}
+	for _, ti := range sorted {
+
+		n := len(res)
+		if n == 0 || !rt.IsOverlap(res[n-1], ti) {
+			ti.idx = n
+			res = append(res, ti)
+			continue
+		}
+
+		last := res[n-1]
+		if ti.Te.After(last.Te) {
+			last.Te = ti.Te
+		}
+		last.Name = joinNames(last.Name, ti.Name)
+		last.Data = p.mergeData(last.Data, ti.Data)
+		last.Times = append(last.Times, ti.Times...)
+	}
+
+	for _, ti := range res {
+		sort.Slice(ti.Times, func(i, j int) bool {
+			return ti.Times[i].Before(ti.Times[j])
+		})
+	}
+
+	return
+}
+
+// Joins names comma-separated skipping empty and repeated ones.
+func joinNames(a, b string) string {
+
+	switch {
+	case b == "":
+		return a
+	case a == "":
+		return b
+	}
+
+	for _, name := range strings.Split(a, ",") {
+		if name == b {
+			return a
+		}
+	}
+	return a + "," + b
+}
+
+//------------------------------------------------------------
+// Interval Set transformations
+//------------------------------------------------------------
+
+// Shift returns set moved by d.
+func (s IntervalSet) Shift(d time.Duration) IntervalSet {
+	return NewIntervalSet(s.tis.Shift(d)...)
+}
+
+// Expand returns set with each member padded
+// by left and right durations.
+func (s IntervalSet) Expand(left, right time.Duration) IntervalSet {
+	return NewIntervalSet(s.tis.Expand(left, right)...)
+}
+
+// Shrink returns set with left and right durations
+// cut off each member.
+func (s IntervalSet) Shrink(left, right time.Duration) IntervalSet {
+	return NewIntervalSet(s.tis.Shrink(left, right)...)
+}
+
+// Clamp returns set limited to bounds.
+func (s IntervalSet) Clamp(bounds *TimeInterval) IntervalSet {
+	return NewIntervalSet(s.tis.Clamp(bounds)...)
+}
+
+// Scale returns set stretched by factor relative to anchor.
+func (s IntervalSet) Scale(factor float64, anchor time.Time) IntervalSet {
+	return NewIntervalSet(s.tis.Scale(factor, anchor)...)
+}
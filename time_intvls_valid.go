@@ -0,0 +1,74 @@
+// Validation of time intervals invariants.
+package intvl
+
+import (
+	"fmt"
+	"sort"
+)
+
+//------------------------------------------------------------
+// Time Intervals validation
+//------------------------------------------------------------
+
+// Validate checks invariants of each interval, see TimeInterval.Validate,
+// and that intervals are sorted by Ts.
+// Rule VALIDATE_NO_OVERLAP additionally requires that intervals
+// don't overlap according to default runtime boundary semantics.
+// Rule VALIDATE_ALLOW_ZERO_DT accepts zero Dt in homogenous mode.
+// Returns ValidationErrors listing all violations.
+func (tis TimeIntervals) Validate(rules ...string) error {
+
+	var errs ValidationErrors
+	allowZeroDt := hasRule(rules, VALIDATE_ALLOW_ZERO_DT)
+
+	for i, ti := range tis {
+		errs = append(errs, ti.validate(i, allowZeroDt)...)
+
+		if i > 0 && ti.Ts.Before(tis[i-1].Ts) {
+			errs = append(errs, &ValidationError{
+				Idx:  i,
+				Rule: INVALID_SORT,
+				Msg:  "starts before previous interval",
+			})
+		}
+	}
+
+	if hasRule(rules, VALIDATE_NO_OVERLAP) {
+		errs = append(errs, tis.validateOverlaps(defaultRuntime())...)
+	}
+
+	return errs.err()
+}
+
+// Reports each interval that overlaps any interval starting before it.
+func (tis TimeIntervals) validateOverlaps(rt *Runtime) (errs ValidationErrors) {
+
+	// Positions ordered by start
+	order := make([]int, len(tis))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return tis[order[a]].Ts.Before(tis[order[b]].Ts)
+	})
+
+	// Interval reaching furthest right so far
+	last := -1
+	for _, i := range order {
+		ti := tis[i]
+
+		if last != -1 && rt.IsOverlap(tis[last], ti) {
+			errs = append(errs, &ValidationError{
+				Idx:  i,
+				Rule: INVALID_OVERLAP,
+				Msg:  fmt.Sprintf("overlaps interval at position %v", last),
+			})
+		}
+
+		if last == -1 || ti.Te.After(tis[last].Te) {
+			last = i
+		}
+	}
+
+	return
+}
@@ -1,6 +1,11 @@
 // TimePoints is a sortable array of points.
 package intvl
 
+import (
+	"sort"
+	"time"
+)
+
 //------------------------------------------------------------
 // Time Points model
 //------------------------------------------------------------
@@ -22,3 +27,46 @@ func (s TimePoints) Swap(i, j int) {
 func (s TimePoints) Less(i, j int) bool {
 	return s[i].T.Before(s[j].T)
 }
+
+//------------------------------------------------------------
+// Time Points creation
+//------------------------------------------------------------
+
+// Points returns start and end points of intervals
+// sorted by time. Idx of each point is position of
+// its interval within tis, intervals are not modified.
+func (tis TimeIntervals) Points() TimePoints {
+
+	points := make(TimePoints, 0, len(tis)*2)
+	for i, ti := range tis {
+		points = append(points,
+			TimePoint{T: ti.Ts, Type: "s", Idx: i},
+			TimePoint{T: ti.Te, Type: "e", Idx: i},
+		)
+	}
+
+	sort.Stable(points)
+	return points
+}
+
+// Groups points of intervals of positive length by time
+// and calls fn for each group in order of time, so that
+// all changes at same time are applied together.
+func (tis TimeIntervals) sweepPoints(fn func(t time.Time, group TimePoints)) {
+
+	points := tis.Points()
+	for i := 0; i < len(points); {
+
+		t := points[i].T
+		group := TimePoints{}
+		for ; i < len(points) && points[i].T.Equal(t); i++ {
+			if tis[points[i].Idx].Len() > 0 {
+				group = append(group, points[i])
+			}
+		}
+
+		if len(group) != 0 {
+			fn(t, group)
+		}
+	}
+}